@@ -24,6 +24,9 @@ import (
 	"strings"
 
 	"github.com/Ankr-network/ankrctl/commands/displayers"
+	gwdcmgr "github.com/Ankr-network/dccn-common/protos/gateway/dcmgr/v1"
+	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
+	gwusermgr "github.com/Ankr-network/dccn-common/protos/gateway/usermgr/v1"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -40,6 +43,15 @@ var AnkrCmd = &Command{
 	Command: &cobra.Command{
 		Use:   "ankrctl",
 		Short: "ankrctl is a command line interface for the Ankr DCCN Hub.",
+		Long: `ankrctl is a command line interface for the Ankr DCCN Hub.
+
+The global flags --output, --context, --hub-url, --access-token, --verbose
+and --trace can also be set with the ANKR_OUTPUT, ANKR_CONTEXT, ANKR_HUB_URL,
+ANKR_ACCESS_TOKEN, ANKR_VERBOSE and ANKR_TRACE environment variables or in the
+config file. A flag takes precedence over the environment, which takes
+precedence over the config file, which takes precedence over the default.
+Values given by flag or environment only apply to the current invocation and
+are never written back to the config file.`,
 	},
 }
 
@@ -73,12 +85,35 @@ var cfgFile string
 var cfgFileWriter = defaultConfigFileWriter
 
 // ErrNoAccessToken is an error for when there is no access token.
-var ErrNoAccessToken = errors.New("no access token has been configured")
+var ErrNoAccessToken = errors.New("no ankr network access token found")
+
+// globalKeys are the config keys backed by the root persistent flags.
+var globalKeys = []string{
+	types.ArgOutput,
+	types.ArgContext,
+	types.ArgHubURL,
+	types.ArgAccessToken,
+	types.ArgVerbose,
+	types.ArgTrace,
+}
 
 func init() {
 	cobra.OnInitialize(initConfig)
 
+	rootPFlagSet := AnkrCmd.PersistentFlags()
+	rootPFlagSet.StringVarP(&Output, types.ArgOutput, types.ArgShortOutput, "text", "output format [text|wide|json|yaml|csv|tsv|go-template=...|go-template-file=...|jsonpath=...]")
+	rootPFlagSet.StringVarP(&Context, types.ArgContext, "", "", "authentication context to use")
+	rootPFlagSet.StringVarP(&HubURL, types.ArgHubURL, "", "", fmt.Sprintf("hub address (default %q)", clientURL))
+	rootPFlagSet.StringVarP(&Token, types.ArgAccessToken, "", "", "access token, overrides the one saved by 'user login'")
+	rootPFlagSet.BoolVarP(&Verbose, types.ArgVerbose, types.ArgShortVerbose, false, "print each hub call and its duration to stderr")
+	rootPFlagSet.BoolVarP(&Trace, types.ArgTrace, "", false, "print each hub call with its request and response to stderr")
+
+	for _, key := range globalKeys {
+		viper.BindPFlag(key, rootPFlagSet.Lookup(key))
+	}
+
 	viper.SetEnvPrefix("ANKR")
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 	viper.BindEnv("hub-url", "ANKR_HUB_URL")
 	viper.SetDefault("hub-url", clientURL)
 	addCommands()
@@ -107,6 +142,13 @@ func initConfig() {
 
 	viper.SetDefault("output", "text")
 	viper.SetDefault("context", "default")
+
+	Output = viper.GetString(types.ArgOutput)
+	Context = viper.GetString(types.ArgContext)
+	HubURL = viper.GetString(types.ArgHubURL)
+	Token = viper.GetString(types.ArgAccessToken)
+	Verbose = viper.GetBool(types.ArgVerbose)
+	Trace = viper.GetBool(types.ArgTrace)
 }
 
 func findConfig() (string, error) {
@@ -160,6 +202,7 @@ func addCommands() {
 	AnkrCmd.AddCommand(chartCmd())
 	AnkrCmd.AddCommand(userCmd())
 	AnkrCmd.AddCommand(walletCmd())
+	AnkrCmd.AddCommand(authCmd())
 }
 
 type flagOpt func(c *Command, name, key string)
@@ -235,6 +278,18 @@ func AddStringSliceFlag(cmd *Command, name, shorthand string, def []string, desc
 	}
 }
 
+// AddStringArrayFlag adds a string array flag to a command. Unlike a string
+// slice flag, its values are not split on commas.
+func AddStringArrayFlag(cmd *Command, name, shorthand string, def []string, desc string, opts ...flagOpt) {
+	fn := flagName(cmd, name)
+	cmd.Flags().StringArrayP(name, shorthand, def, desc)
+	viper.BindPFlag(fn, cmd.Flags().Lookup(name))
+
+	for _, o := range opts {
+		o(cmd, name, fn)
+	}
+}
+
 func flagName(cmd *Command, name string) string {
 	parentName := types.NSRoot
 	if cmd.Parent() != nil {
@@ -263,62 +318,55 @@ type CmdConfig struct {
 	Out  io.Writer
 	Args []string
 
-	getContextAccessToken func() (string, string)
-	setContextAccessToken func(string, string)
+	// hub clients
+	hub     *HubClient
+	session *tokenManager
+	AppMgr  func() (gwtaskmgr.AppMgrClient, error)
+	UserMgr func() (gwusermgr.UserMgrClient, error)
+	DCAPI   func() (gwdcmgr.DCAPIClient, error)
 }
 
 // NewCmdConfig creates an instance of a CmdConfig.
 func NewCmdConfig(ns string, dc types.Config, out io.Writer, args []string) (*CmdConfig, error) {
 
+	hub := NewHubClient(hubURL(), func() string { return Token })
+	hub.Verbose = Verbose
+	hub.Trace = Trace
+
+	// a token given with --access-token is used as is, the one saved by
+	// 'user login' is refreshed when it expires
+	var session *tokenManager
+	if Token == "" {
+		session = newTokenManager(currentContext(), hub.UserMgr)
+		hub.Token = session.Token
+		hub.Refresh = session.Refresh
+	}
+
 	cmdConfig := &CmdConfig{
 		NS:   ns,
 		Ankr: dc,
 		Out:  out,
 		Args: args,
 
-		getContextAccessToken: func() (string, string) {
-			context := Context
-			if context == "" {
-				context = viper.GetString("context")
-			}
-			token := ""
-			userid := ""
-
-			switch context {
-			case "default":
-				token = viper.GetString(types.ArgAccessToken)
-				userid = viper.GetString(types.ArgUserID)
-			default:
-				contexts := viper.GetStringMapString("auth-contexts")
-				userid = contexts[types.ArgUserID]
-				token = contexts[types.ArgAccessToken]
-			}
-			return token, userid
-		},
-
-		setContextAccessToken: func(token string, userid string) {
-			context := Context
-			if context == "" {
-				context = viper.GetString("context")
-			}
-
-			switch context {
-			case "default":
-				viper.Set(types.ArgAccessToken, token)
-				viper.Set(types.ArgUserID, userid)
-			default:
-				contexts := viper.GetStringMapString("auth-contexts")
-				contexts[types.ArgAccessToken] = token
-				contexts[types.ArgUserID] = userid
-
-				viper.Set("auth-contexts", contexts)
-			}
-		},
+		hub:     hub,
+		session: session,
+		AppMgr:  hub.AppMgr,
+		UserMgr: hub.UserMgr,
+		DCAPI:   hub.DCAPI,
 	}
 
 	return cmdConfig, nil
 }
 
+// Close releases the hub connection opened for the command, if any.
+func (c *CmdConfig) Close() error {
+	if c.hub == nil {
+		return nil
+	}
+
+	return c.hub.Close()
+}
+
 // Display displayes the output from a command.
 func (c *CmdConfig) Display(d displayers.Displayable) error {
 	dc := &displayers.Displayer{
@@ -331,6 +379,18 @@ func (c *CmdConfig) Display(d displayers.Displayable) error {
 	return dc.Display()
 }
 
+// isTableOutput reports whether the output is a text table, which commands
+// may follow with free-form sections that have no column.
+func isTableOutput() bool {
+	return Output == "" || Output == "text" || Output == "wide"
+}
+
+// colorTable reports whether a table written to w may be coloured: it goes to
+// a terminal and colours aren't turned off.
+func colorTable(w io.Writer) bool {
+	return isTableOutput() && isTerminal(w) && !color.NoColor
+}
+
 // CmdBuilder builds a new command.
 func CmdBuilder(parent *Command, cr CmdRunner, cliText, desc string, out io.Writer, options ...cmdOption) *Command {
 	return cmdBuilderWithInit(parent, cr, cliText, desc, out, options...)
@@ -351,6 +411,7 @@ func cmdBuilderWithInit(parent *Command, cr CmdRunner, cliText, desc string, out
 			checkErr(err, cmd)
 
 			err = cr(c)
+			c.Close()
 			checkErr(err, cmd)
 		},
 	}
@@ -372,6 +433,10 @@ func cmdBuilderWithInit(parent *Command, cr CmdRunner, cliText, desc string, out
 		AddBoolFlag(c, types.ArgNoHeader, "", false, "hide headers")
 	}
 
+	if c.sortable {
+		AddStringFlag(c, types.ArgSortBySlug, "", "", "Column to sort by, prefix it with - to sort in descending order")
+	}
+
 	return c
 
 }
@@ -384,7 +449,22 @@ func writeConfig() error {
 
 	defer f.Close()
 
-	b, err := yaml.Marshal(viper.AllSettings())
+	settings := viper.AllSettings()
+	for _, key := range globalKeys {
+		if !isTransient(key) {
+			continue
+		}
+
+		delete(settings, key)
+		if v, ok := storedSetting(key); ok {
+			settings[key] = v
+		}
+	}
+	if savedAuthContexts != nil {
+		settings[authContextsKey] = savedAuthContexts
+	}
+
+	b, err := yaml.Marshal(settings)
 	if err != nil {
 		return errors.New("unable to encode configuration to YAML format")
 	}
@@ -397,6 +477,30 @@ func writeConfig() error {
 	return nil
 }
 
+// isTransient reports whether the value of a global key was given on the
+// command line or in the environment for this invocation only.
+func isTransient(key string) bool {
+	if f := AnkrCmd.PersistentFlags().Lookup(key); f != nil && f.Changed {
+		return true
+	}
+
+	env := "ANKR_" + strings.ToUpper(strings.Replace(key, "-", "_", -1))
+	_, ok := os.LookupEnv(env)
+	return ok
+}
+
+// storedSetting returns the value of key as it is saved in the config file.
+func storedSetting(key string) (interface{}, bool) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.SetConfigFile(cfgFile)
+	if err := v.ReadInConfig(); err != nil || !v.IsSet(key) {
+		return nil, false
+	}
+
+	return v.Get(key), true
+}
+
 func defaultConfigFileWriter() (io.WriteCloser, error) {
 	f, err := os.Create(cfgFile)
 	if err != nil {
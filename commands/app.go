@@ -16,32 +16,16 @@ package commands
 import (
 	"fmt"
 	"github.com/Ankr-network/ankrctl/types"
-	"log"
-	"strconv"
-	"sync"
-	"time"
-
-	"github.com/spf13/viper"
 
 	"context"
 
 	"github.com/Ankr-network/ankrctl/commands/displayers"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
-	gwusermgr "github.com/Ankr-network/dccn-common/protos/gateway/usermgr/v1"
-	"github.com/gobwas/glob"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc/status"
-
-	ankr_const "github.com/Ankr-network/dccn-common"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
 )
 
-var port = ":" + strconv.Itoa(ankr_const.DefaultPort)
-
-var clientURL = "client.dccn.ankr.com"
-
 // App creates the app command.
 func appCmd() *Command {
 	//DCCN-CLI app
@@ -58,46 +42,72 @@ func appCmd() *Command {
 
 	//DCCN-CLI comput app create
 	cmdRunAppCreate := CmdBuilder(cmd, RunAppCreate, "create <app-name> [app-name ...]",
-		"create app", Writer, aliasOpt("cr"), docCategories("app"))
+		"create app", Writer, aliasOpt("cr"), displayerType(&displayers.BatchResult{}), docCategories("app"))
 	AddStringFlag(cmdRunAppCreate, types.ArgChartNameSlug, "", "", "Chart name", requiredOpt())
 	AddStringFlag(cmdRunAppCreate, types.ArgChartRepoSlug, "", "", "Chart repo", requiredOpt())
-	AddStringFlag(cmdRunAppCreate, types.ArgChartVersionSlug, "", "", "Chart version", requiredOpt())
+	AddStringFlag(cmdRunAppCreate, types.ArgChartVersionSlug, "", "", "Chart version, or a constraint such as ^1.2 or latest", requiredOpt())
 	AddStringFlag(cmdRunAppCreate, types.ArgNsIDSlug, "", "", "Namespace ID")
-	AddStringFlag(cmdRunAppCreate, types.ArgNsClusterIDSlug, "", "", "Namespace Cluster ID")
+	AddStringFlag(cmdRunAppCreate, types.ArgNsClusterIDSlug, "", "", "Namespace Cluster ID, or auto for the recommended cluster")
 	AddStringFlag(cmdRunAppCreate, types.ArgNsNameSlug, "", "", "Namespace Name")
-	AddStringFlag(cmdRunAppCreate, types.ArgNsCpuLimitSlug, "", "", "Namespace CPU Limit (mCPUs)")
-	AddStringFlag(cmdRunAppCreate, types.ArgNsMemLimitSlug, "", "", "Namespace MEM Limit (MBs)")
-	AddStringFlag(cmdRunAppCreate, types.ArgNsStorageLimitSlug, "", "", "Namespace Storage Limit (GBs)")
+	AddStringFlag(cmdRunAppCreate, types.ArgNsCpuLimitSlug, "", "", "Namespace CPU Limit (in vCPUs, or mCPUs such as 500m)")
+	AddStringFlag(cmdRunAppCreate, types.ArgNsMemLimitSlug, "", "", "Namespace MEM Limit (in GiB, or with a unit such as 512Mi)")
+	AddStringFlag(cmdRunAppCreate, types.ArgNsStorageLimitSlug, "", "", "Namespace Storage Limit (in GiB, or with a unit such as 20G)")
+	waitFlags(cmdRunAppCreate, "Wait until the apps are running")
+	batchFlags(cmdRunAppCreate)
 
 	//DCCN-CLI comput app cancel
 	cmdRunAppCancel := CmdBuilder(cmd, RunAppCancel, "cancel <app-id> [app-id ...]",
-		"Cancel app by id", Writer, aliasOpt("dl"), docCategories("app"))
+		"Cancel app by id", Writer, aliasOpt("dl"), displayerType(&displayers.BatchResult{}), docCategories("app"))
 	AddBoolFlag(cmdRunAppCancel, types.ArgForce, types.ArgShortForce, false, "Force app cancel")
+	waitFlags(cmdRunAppCancel, "Wait until the apps are canceled")
+	batchFlags(cmdRunAppCancel)
 
 	//DCCN-CLI comput app purge
 	cmdRunAppPurge := CmdBuilder(cmd, RunAppPurge, "purge <app-id> [app-id ...]", "Purge app by id",
-		Writer, aliasOpt("rm"), docCategories("app"))
+		Writer, aliasOpt("rm"), displayerType(&displayers.BatchResult{}), docCategories("app"))
 	AddBoolFlag(cmdRunAppPurge, types.ArgForce, types.ArgShortForce, false, "Force app purge")
+	waitFlags(cmdRunAppPurge, "Wait until the apps are deleted")
+	batchFlags(cmdRunAppPurge)
 
 	//DCCN-CLI comput app update
 	cmdRunAppUpdate := CmdBuilder(cmd, RunAppUpdate, "update <app-id> [app-id ...]",
-		"Update app by id", Writer, aliasOpt("ud"), docCategories("app"))
+		"Update app by id", Writer, aliasOpt("ud"), displayerType(&displayers.BatchResult{}), docCategories("app"))
 	AddStringFlag(cmdRunAppUpdate, types.ArgAppNameSlug, "", "", "App name")
-	AddStringFlag(cmdRunAppUpdate, types.ArgUpdateVersionSlug, "", "", "Update version")
+	AddStringFlag(cmdRunAppUpdate, types.ArgUpdateVersionSlug, "", "", "Update version, or a constraint such as ^1.2 or latest")
+	waitFlags(cmdRunAppUpdate, "Wait until the apps are running")
+	batchFlags(cmdRunAppUpdate)
+
+	//DCCN-CLI app wait
+	cmdRunAppWait := CmdBuilder(cmd, RunAppWait, "wait <app-id> [app-id ...]",
+		"wait until apps reach a status", Writer, docCategories("app"))
+	AddStringFlag(cmdRunAppWait, types.ArgForSlug, "", "status=running", "Condition to wait for: status=<status> or delete")
+	AddStringFlag(cmdRunAppWait, types.ArgTimeoutSlug, "", defaultWaitTimeout, "How long to wait, such as 30s or 10m")
+
+	//DCCN-CLI app apply
+	cmdRunAppApply := CmdBuilder(cmd, RunAppApply, "apply", "create or update apps from a manifest", Writer,
+		displayerType(&displayers.ApplyResult{}), docCategories("app"))
+	AddStringFlag(cmdRunAppApply, types.ArgFileSlug, types.ArgShortFile, "", "Manifest file (yaml or json), - for stdin", requiredOpt())
+	AddBoolFlag(cmdRunAppApply, types.ArgDryRunSlug, "", false, "Show the changes without applying them")
+
+	//DCCN-CLI app diff
+	cmdRunAppDiff := CmdBuilder(cmd, RunAppDiff, "diff", "show how the live apps differ from a manifest", Writer,
+		docCategories("app"))
+	AddStringFlag(cmdRunAppDiff, types.ArgFileSlug, types.ArgShortFile, "", "Manifest file (yaml or json), - for stdin", requiredOpt())
 
 	//DCCN-CLI app list
 	cmdRunAppList := CmdBuilder(cmd, RunAppList, "list [GLOB]", "list apps", Writer,
 		aliasOpt("ls"), displayerType(&displayers.AppReport{}), docCategories("app"))
-	_ = cmdRunAppList
+	filterFlags(cmdRunAppList)
+	watchFlags(cmdRunAppList)
 
 	//DCCN-CLI app detail
-	cmdRunAppDetail := CmdBuilder(cmd, RunAppDetail, "detail <app-id>", "list app detail", Writer,
-		aliasOpt("dt"), docCategories("app"))
+	cmdRunAppDetail := CmdBuilder(cmd, RunAppDetail, "detail <app-id> [app-id ...]", "list app detail", Writer,
+		aliasOpt("dt"), displayerType(&displayers.AppDetail{}), docCategories("app"))
 	_ = cmdRunAppDetail
 
 	//DCCN-CLI app overview
 	cmdRunAppOverview := CmdBuilder(cmd, RunAppOverview, "overview", "show apps overview", Writer,
-		aliasOpt("ov"), docCategories("app"))
+		aliasOpt("ov"), displayerType(&displayers.AppOverview{}), docCategories("app"))
 	_ = cmdRunAppOverview
 
 	return cmd
@@ -129,6 +139,11 @@ func RunAppCreate(c *CmdConfig) error {
 		return err
 	}
 
+	chartver, err = resolveChartVersion(c, chartname, chartrepo, chartver)
+	if err != nil {
+		return err
+	}
+
 	createAppRequest.Chart = &gwtaskmgr.Chart{
 		ChartName: chartname,
 		ChartRepo: chartrepo,
@@ -157,9 +172,9 @@ func RunAppCreate(c *CmdConfig) error {
 			return err
 		}
 
-		nsCpuLimit, err := strconv.ParseFloat(cpuLimit, 32)
-		if nsCpuLimit == 0 || err != nil {
-			return fmt.Errorf("Cpu Limit %s is not a valid number", cpuLimit)
+		nsCpuLimit, err := parseCpuLimit(cpuLimit)
+		if err != nil {
+			return err
 		}
 
 		memLimit, err := c.Ankr.GetString(c.NS, types.ArgNsMemLimitSlug)
@@ -167,9 +182,9 @@ func RunAppCreate(c *CmdConfig) error {
 			return err
 		}
 
-		nsMemLimit, err := strconv.ParseFloat(memLimit, 32)
-		if nsMemLimit == 0 || err != nil {
-			return fmt.Errorf("Mem Limit %s is not a valid number", memLimit)
+		nsMemLimit, err := parseMemLimit(memLimit)
+		if err != nil {
+			return err
 		}
 
 		storageLimit, err := c.Ankr.GetString(c.NS, types.ArgNsStorageLimitSlug)
@@ -177,9 +192,9 @@ func RunAppCreate(c *CmdConfig) error {
 			return err
 		}
 
-		nsStorageLimit, err := strconv.ParseFloat(storageLimit, 32)
-		if nsStorageLimit == 0 || err != nil {
-			return fmt.Errorf("Storage Limit %s is not a valid number", storageLimit)
+		nsStorageLimit, err := parseStorageLimit(storageLimit)
+		if err != nil {
+			return err
 		}
 
 		nsClusterID, err := c.Ankr.GetString(c.NS, types.ArgNsClusterIDSlug)
@@ -187,64 +202,53 @@ func RunAppCreate(c *CmdConfig) error {
 			return err
 		}
 
+		nsClusterID, err = resolveClusterID(c, nsClusterID, nsCpuLimit, nsMemLimit, nsStorageLimit)
+		if err != nil {
+			return err
+		}
+
 		createAppRequest.Namespace = &gwtaskmgr.Namespace{
 			NsName:         nsname,
-			NsCpuLimit:     uint32(nsCpuLimit * 1000),
-			NsMemLimit:     uint32(nsMemLimit * 1024),
-			NsStorageLimit: uint32(nsStorageLimit * 1024),
+			NsCpuLimit:     nsCpuLimit,
+			NsMemLimit:     nsMemLimit,
+			NsStorageLimit: nsStorageLimit,
 			ClusterId:      nsClusterID,
 		}
 	}
 
-	url := viper.GetString("hub-url")
-
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
+	wait, timeout, err := waitOptions(c)
+	if err != nil {
+		return err
 	}
 
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	appClient, err := c.AppMgr()
 	if err != nil {
-		log.Fatalf("Did not connect: %v", err)
+		return err
 	}
 
-	defer conn.Close()
-	appClient := gwtaskmgr.NewAppMgrClient(conn)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	var wg sync.WaitGroup
-	errs := make(chan error, len(c.Args))
-	for _, name := range c.Args {
-		createAppRequest.AppName = name
-
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			rsp, err := appClient.CreateApp(tokenctx, createAppRequest)
-			if err != nil {
-				errs <- err
-			} else {
-				if rsp != nil {
-					fmt.Printf("App create success with id: %s, please use 'app list' and 'app detail' to check the current status.\n", rsp.AppId)
-				}
-			}
-		}()
+	b, err := newBatch(c, "create")
+	if err != nil {
+		return err
 	}
-	wg.Wait()
-	close(errs)
 
-	for err := range errs {
+	results := b.run(c.Args, func(name string) (string, error) {
+		rsp, err := appClient.CreateApp(context.Background(), &gwtaskmgr.CreateAppRequest{
+			AppName:   name,
+			Chart:     createAppRequest.Chart,
+			NsId:      createAppRequest.NsId,
+			Namespace: createAppRequest.Namespace,
+		})
 		if err != nil {
-			return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+			return "", err
 		}
+		return rsp.AppId, nil
+	})
+	if err := c.displayBatch(results); err != nil {
+		return err
+	}
+
+	if wait {
+		return waitForApps(appClient, batchSucceeded(results), appCondition{status: "running"}, timeout)
 	}
 
 	return nil
@@ -262,42 +266,34 @@ func RunAppPurge(c *CmdConfig) error {
 		return types.NewMissingArgsErr(c.NS)
 	}
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
+	wait, timeout, err := waitOptions(c)
+	if err != nil {
+		return err
 	}
 
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
 	if force || AskForConfirm(fmt.Sprintf("Are you sure you want to Purge %d app(s) (y/N) ? ", len(c.Args))) == nil {
-		url := viper.GetString("hub-url")
+		appClient, err := c.AppMgr()
+		if err != nil {
+			return err
+		}
 
-		conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+		b, err := newBatch(c, "purge")
 		if err != nil {
-			log.Fatalf("Did not connect: %v", err)
+			return err
 		}
-		defer conn.Close()
-		appClient := gwtaskmgr.NewAppMgrClient(conn)
-
-		fn := func(ids []string) error {
-			for _, id := range ids {
-				_, err := appClient.PurgeApp(tokenctx, &gwtaskmgr.AppID{AppId: id})
-				if err != nil {
-					return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
-				}
-				fmt.Printf("App %s purge success.\n", id)
-			}
-			return nil
+
+		results := b.run(c.Args, func(id string) (string, error) {
+			_, err := appClient.PurgeApp(context.Background(), &gwtaskmgr.AppID{AppId: id})
+			return id, err
+		})
+		if err := c.displayBatch(results); err != nil {
+			return err
 		}
-		return fn(c.Args)
 
+		if wait {
+			return waitForApps(appClient, batchSucceeded(results), appCondition{deleted: true}, timeout)
+		}
+		return nil
 	}
 	return fmt.Errorf("Operation aborted")
 
@@ -306,20 +302,6 @@ func RunAppPurge(c *CmdConfig) error {
 // RunAppCancel destroy a app by id.
 func RunAppCancel(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
 	force, err := c.Ankr.GetBool(c.NS, types.ArgForce)
 	if err != nil {
 		return err
@@ -329,29 +311,34 @@ func RunAppCancel(c *CmdConfig) error {
 		return types.NewMissingArgsErr(c.NS)
 	}
 
-	if force || AskForConfirm(fmt.Sprintf("Are you sure you want to Cancel %d app(s) (y/N) ? ", len(c.Args))) == nil {
-		url := viper.GetString("hub-url")
+	wait, timeout, err := waitOptions(c)
+	if err != nil {
+		return err
+	}
 
-		conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	if force || AskForConfirm(fmt.Sprintf("Are you sure you want to Cancel %d app(s) (y/N) ? ", len(c.Args))) == nil {
+		appClient, err := c.AppMgr()
 		if err != nil {
-			log.Fatalf("Did not connect: %v", err)
+			return err
 		}
 
-		defer conn.Close()
-		appClient := gwtaskmgr.NewAppMgrClient(conn)
+		b, err := newBatch(c, "cancel")
+		if err != nil {
+			return err
+		}
 
-		fn := func(ids []string) error {
-			for _, id := range ids {
-				_, err := appClient.CancelApp(tokenctx, &gwtaskmgr.AppID{AppId: id})
-				if err != nil {
-					return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
-				}
-				fmt.Printf("App %s cancel success.\n", id)
-			}
-			return nil
+		results := b.run(c.Args, func(id string) (string, error) {
+			_, err := appClient.CancelApp(context.Background(), &gwtaskmgr.AppID{AppId: id})
+			return id, err
+		})
+		if err := c.displayBatch(results); err != nil {
+			return err
 		}
 
-		return fn(c.Args)
+		if wait {
+			return waitForApps(appClient, batchSucceeded(results), appCondition{status: "canceled"}, timeout)
+		}
+		return nil
 	}
 	return fmt.Errorf("Operation aborted")
 
@@ -360,45 +347,19 @@ func RunAppCancel(c *CmdConfig) error {
 // RunAppList returns a list of apps.
 func RunAppList(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
+	appClient, err := c.AppMgr()
+	if err != nil {
+		return err
 	}
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
 
-	matches := []glob.Glob{}
-	for _, globStr := range c.Args {
-		g, err := glob.Compile(globStr)
+	return c.DisplayList(func() (displayers.Displayable, error) {
+		r, err := appClient.AppList(context.Background(), &common_proto.Empty{})
 		if err != nil {
-			return fmt.Errorf("Unknown glob %q", globStr)
+			return nil, fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 		}
 
-		matches = append(matches, g)
-	}
-
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("Did not connect: %v", err)
-	}
-	defer conn.Close()
-
-	appClient := gwtaskmgr.NewAppMgrClient(conn)
-
-	r, err := appClient.AppList(tokenctx, &common_proto.Empty{})
-	if err != nil {
-		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
-	}
-
-	item := &displayers.AppReport{Apps: r.AppReports}
-	return c.Display(item)
+		return &displayers.AppReport{Apps: r.AppReports}, nil
+	})
 }
 
 // RunAppDetail returns a list of apps.
@@ -408,93 +369,64 @@ func RunAppDetail(c *CmdConfig) error {
 		return types.NewMissingArgsErr(c.NS)
 	}
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	appClient, err := c.AppMgr()
 	if err != nil {
-		log.Fatalf("Did not connect: %v", err)
+		return err
 	}
-	defer conn.Close()
 
-	appClient := gwtaskmgr.NewAppMgrClient(conn)
+	item := &displayers.AppDetail{}
+	for _, id := range c.Args {
+		r, err := appClient.AppDetail(context.Background(), &gwtaskmgr.AppID{AppId: id})
+		if err != nil {
+			return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+		}
+		item.Apps = append(item.Apps, r.AppReport)
+	}
 
-	r, err := appClient.AppDetail(tokenctx, &gwtaskmgr.AppID{AppId: c.Args[0]})
-	if err != nil {
-		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+	if err := c.Display(item); err != nil {
+		return err
 	}
-	fmt.Printf("Application %s resource detail:\n %s \n", c.Args[0], r.AppReport.Detail)
 
+	// the multi-line resource detail follows the table
+	if isTableOutput() {
+		for _, app := range item.Apps {
+			fmt.Fprintf(c.Out, "\nApplication %s resource detail:\n%s\n", app.AppDeployment.AppId, app.Detail)
+		}
+	}
 	return nil
 }
 
 // RunAppOverview returns a overview of apps.
 func RunAppOverview(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("Did not connect: %v", err)
-	}
-	defer conn.Close()
-
-	appClient := gwtaskmgr.NewAppMgrClient(conn)
+	appClient, err := c.AppMgr()
 	if err != nil {
 		return err
 	}
-	tor, err := appClient.AppOverview(tokenctx, &common_proto.Empty{})
+
+	tor, err := appClient.AppOverview(context.Background(), &common_proto.Empty{})
 	if err != nil {
 		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 	}
-	fmt.Printf("Cluster Count:\t\t%v\nNamespace Count:\t%v\nNetwork Count:\t\t%v\nTotal App Count:\t%v\nCluster Count:\t%v\n",
-		tor.ClusterCount, tor.NamespaceCount, tor.NetworkCount, tor.TotalAppCount, tor.ClusterCount)
-	fmt.Printf("Cpu Total:\t\t%v\nCpu Usage:\t%v\nMem Total:\t\t%v\nMem Usage:\t%v\nStorage Total:\t%v\nStorage Usage:\t%v\n",
-		tor.CpuTotal, tor.CpuUsage, tor.MemTotal, tor.MemUsage, tor.StorageTotal, tor.StorageUsage)
+	item := &displayers.AppOverview{Overview: displayers.AppOverviewInfo{
+		ClusterCount:   int64(tor.ClusterCount),
+		NamespaceCount: int64(tor.NamespaceCount),
+		NetworkCount:   int64(tor.NetworkCount),
+		TotalAppCount:  int64(tor.TotalAppCount),
+		CpuTotal:       float64(tor.CpuTotal),
+		CpuUsage:       float64(tor.CpuUsage),
+		MemTotal:       float64(tor.MemTotal),
+		MemUsage:       float64(tor.MemUsage),
+		StorageTotal:   float64(tor.StorageTotal),
+		StorageUsage:   float64(tor.StorageUsage),
+	}}
 
-	return nil
+	return c.Display(item)
 }
 
 // RunAppUpdate updates a app.
 func RunAppUpdate(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
 	if len(c.Args) < 1 {
 		return types.NewMissingArgsErr(c.NS)
 	}
@@ -518,25 +450,52 @@ func RunAppUpdate(c *CmdConfig) error {
 		updateAppRequest.ChartVer = chartver
 	}
 
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	wait, timeout, err := waitOptions(c)
 	if err != nil {
-		log.Fatalf("Did not connect: %v", err)
+		return err
 	}
-	defer conn.Close()
-	appClient := gwtaskmgr.NewAppMgrClient(conn)
 
-	fn := func(ids []string) error {
-		for _, id := range ids {
-			updateAppRequest.AppId = id
+	appClient, err := c.AppMgr()
+	if err != nil {
+		return err
+	}
+
+	b, err := newBatch(c, "update")
+	if err != nil {
+		return err
+	}
+
+	// a version constraint is resolved against the chart of each app
+	resolve := versionConstraint(updateAppRequest.ChartVer) != nil
+
+	results := b.run(c.Args, func(id string) (string, error) {
+		chartVer := updateAppRequest.ChartVer
+		if resolve {
+			r, err := appClient.AppDetail(context.Background(), &gwtaskmgr.AppID{AppId: id})
+			if err != nil {
+				return id, err
+			}
 
-			_, err := appClient.UpdateApp(tokenctx, updateAppRequest)
+			chart := r.AppReport.AppDeployment.ChartDetail
+			chartVer, err = resolveChartVersion(c, chart.ChartName, chart.ChartRepo, chartVer)
 			if err != nil {
-				return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+				return id, err
 			}
-			fmt.Printf("App %s update success.\n", id)
 		}
-		return nil
+
+		_, err := appClient.UpdateApp(context.Background(), &gwtaskmgr.UpdateAppRequest{
+			AppId:    id,
+			AppName:  updateAppRequest.AppName,
+			ChartVer: chartVer,
+		})
+		return id, err
+	})
+	if err := c.displayBatch(results); err != nil {
+		return err
+	}
+
+	if wait {
+		return waitForApps(appClient, batchSucceeded(results), appCondition{status: "running"}, timeout)
 	}
-	return fn(c.Args)
+	return nil
 }
@@ -0,0 +1,476 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Ankr-network/ankrctl/commands/displayers"
+	"github.com/Ankr-network/ankrctl/types"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+)
+
+// Actions planned by 'app apply'.
+const (
+	applyCreate    = "create"
+	applyUpdate    = "update"
+	applyUnchanged = "unchanged"
+)
+
+// applyPlan is what 'app apply' does for a single app of a manifest.
+type applyPlan struct {
+	App     AppManifest
+	Action  string
+	AppID   string
+	Changes []string
+
+	// chartVer is the chart version an existing app is updated to.
+	chartVer string
+	// nsID is the namespace a new app is created in, newNamespace is
+	// created first when the namespace does not exist yet.
+	nsID         string
+	newNamespace *gwtaskmgr.CreateNamespaceRequest
+	// nsUpdate changes the limits of the namespace of the app.
+	nsUpdate *gwtaskmgr.UpdateNamespaceRequest
+	// err is a conflict found while planning, the app is left alone.
+	err error
+}
+
+// planApply compares the apps of a manifest with the live apps and
+// namespaces of the hub and plans how to bring them in line.
+func planApply(m *Manifest, apps []*common_proto.AppReport, namespaces []*common_proto.NamespaceReport) []*applyPlan {
+	plans := []*applyPlan{}
+	newNamespaces := map[string]*gwtaskmgr.CreateNamespaceRequest{}
+	nsUpdates := map[string]*gwtaskmgr.UpdateNamespaceRequest{}
+
+	for _, app := range m.Apps {
+		p := &applyPlan{App: app, Action: applyUnchanged}
+		plans = append(plans, p)
+
+		live, err := findApp(apps, app.Name)
+		if err != nil {
+			p.err = err
+			continue
+		}
+
+		ns, err := findNamespace(namespaces, app.Namespace)
+		if err != nil {
+			p.err = err
+			continue
+		}
+
+		target, err := app.target()
+		if err != nil {
+			p.err = err
+			continue
+		}
+
+		if live == nil {
+			p.Action = applyCreate
+			p.Changes = append(p.Changes, fmt.Sprintf("chart %s/%s@%s", target.Repo, target.Name, target.Version))
+
+			if ns == nil {
+				if app.Namespace.ID != "" {
+					p.err = fmt.Errorf("namespace %s not found", app.Namespace.ID)
+					continue
+				}
+
+				req, ok := newNamespaces[app.Namespace.Name]
+				if !ok {
+					if req, err = newNamespaceRequest(app.Namespace); err != nil {
+						p.err = err
+						continue
+					}
+					newNamespaces[app.Namespace.Name] = req
+				}
+
+				p.newNamespace = req
+				p.Changes = append(p.Changes, fmt.Sprintf("namespace %s (new)", app.Namespace.Name))
+				continue
+			}
+
+			p.nsID = ns.Namespace.NsId
+		} else {
+			p.AppID = live.AppDeployment.AppId
+
+			liveChart := live.AppDeployment.ChartDetail
+			if liveChart.ChartRepo != target.Repo || liveChart.ChartName != target.Name {
+				p.err = fmt.Errorf("chart %s/%s of app %s cannot be changed to %s/%s in place, cancel and recreate the app",
+					liveChart.ChartRepo, liveChart.ChartName, app.Name, target.Repo, target.Name)
+				continue
+			}
+
+			liveNs := live.AppDeployment.Namespace
+			if ns == nil || ns.Namespace.NsId != liveNs.NsId {
+				p.err = fmt.Errorf("namespace %s of app %s cannot be changed in place, cancel and recreate the app",
+					liveNs.NsName, app.Name)
+				continue
+			}
+
+			if liveChart.ChartVer != target.Version {
+				p.Action = applyUpdate
+				p.chartVer = target.Version
+				if sourceVersion(liveChart.ChartVer) != app.Chart.Version {
+					p.Changes = append(p.Changes, fmt.Sprintf("chart version %s -> %s",
+						sourceVersion(liveChart.ChartVer), app.Chart.Version))
+				}
+				if valuesVersion(liveChart.ChartVer) != valuesVersion(target.Version) {
+					p.Changes = append(p.Changes, "values")
+				}
+			}
+		}
+
+		if !app.Namespace.hasLimits() {
+			continue
+		}
+
+		req, changes, err := namespaceUpdateRequest(ns.Namespace, app.Namespace)
+		if err != nil {
+			p.err = err
+			continue
+		}
+		if req == nil {
+			continue
+		}
+
+		if planned, ok := nsUpdates[req.NsId]; ok {
+			if planned.NsCpuLimit != req.NsCpuLimit || planned.NsMemLimit != req.NsMemLimit ||
+				planned.NsStorageLimit != req.NsStorageLimit {
+				p.err = fmt.Errorf("namespace %s is given different limits by several apps", ns.Namespace.NsName)
+			}
+			continue
+		}
+		nsUpdates[req.NsId] = req
+
+		p.nsUpdate = req
+		p.Changes = append(p.Changes, changes...)
+		if p.Action == applyUnchanged {
+			p.Action = applyUpdate
+		}
+	}
+
+	return plans
+}
+
+// appGone reports whether an app was canceled or purged, so its name can be
+// taken by a new app.
+func appGone(r *common_proto.AppReport) bool {
+	s := strings.ToLower(r.AppStatus.String())
+	return strings.HasSuffix(s, "canceled") || strings.HasSuffix(s, "cancelled") || strings.HasSuffix(s, "purged")
+}
+
+// findApp returns the live app called name, or nil if there is none.
+func findApp(apps []*common_proto.AppReport, name string) (*common_proto.AppReport, error) {
+	var found *common_proto.AppReport
+	for _, r := range apps {
+		if r.AppDeployment == nil || r.AppDeployment.AppName != name || appGone(r) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("more than one app is called %s, remove the duplicates first", name)
+		}
+		found = r
+	}
+
+	return found, nil
+}
+
+// findNamespace returns the namespace selected by ref, or nil if there is none.
+func findNamespace(namespaces []*common_proto.NamespaceReport, ref NamespaceRef) (*common_proto.NamespaceReport, error) {
+	var found *common_proto.NamespaceReport
+	for _, r := range namespaces {
+		if r.Namespace == nil {
+			continue
+		}
+		if ref.ID != "" && r.Namespace.NsId != ref.ID {
+			continue
+		}
+		if ref.ID == "" && r.Namespace.NsName != ref.Name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("more than one namespace is called %s, select it by id", ref.Name)
+		}
+		found = r
+	}
+
+	return found, nil
+}
+
+// newNamespaceRequest returns the request creating the namespace ref.
+func newNamespaceRequest(ref NamespaceRef) (*gwtaskmgr.CreateNamespaceRequest, error) {
+	if ref.CpuLimit == "" || ref.MemLimit == "" || ref.StorageLimit == "" {
+		return nil, fmt.Errorf("namespace %s does not exist, cpuLimit, memLimit and storageLimit are required to create it", ref.Name)
+	}
+
+	req := &gwtaskmgr.CreateNamespaceRequest{NsName: ref.Name, ClusterId: ref.ClusterID}
+
+	var err error
+	if req.NsCpuLimit, err = parseCpuLimit(ref.CpuLimit); err != nil {
+		return nil, err
+	}
+	if req.NsMemLimit, err = parseMemLimit(ref.MemLimit); err != nil {
+		return nil, err
+	}
+	if req.NsStorageLimit, err = parseStorageLimit(ref.StorageLimit); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// namespaceUpdateRequest returns the request changing the limits of live to
+// the ones given by ref, or nil if they already match. Limits left out of
+// ref keep their live value.
+func namespaceUpdateRequest(live *common_proto.Namespace, ref NamespaceRef) (*gwtaskmgr.UpdateNamespaceRequest, []string, error) {
+	req := &gwtaskmgr.UpdateNamespaceRequest{
+		NsId:           live.NsId,
+		NsCpuLimit:     live.NsCpuLimit,
+		NsMemLimit:     live.NsMemLimit,
+		NsStorageLimit: live.NsStorageLimit,
+	}
+
+	var err error
+	if ref.CpuLimit != "" {
+		if req.NsCpuLimit, err = parseCpuLimit(ref.CpuLimit); err != nil {
+			return nil, nil, err
+		}
+	}
+	if ref.MemLimit != "" {
+		if req.NsMemLimit, err = parseMemLimit(ref.MemLimit); err != nil {
+			return nil, nil, err
+		}
+	}
+	if ref.StorageLimit != "" {
+		if req.NsStorageLimit, err = parseStorageLimit(ref.StorageLimit); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	changes := []string{}
+	if req.NsCpuLimit != live.NsCpuLimit {
+		changes = append(changes, fmt.Sprintf("namespace cpu limit %d -> %d mCPUs", live.NsCpuLimit, req.NsCpuLimit))
+	}
+	if req.NsMemLimit != live.NsMemLimit {
+		changes = append(changes, fmt.Sprintf("namespace mem limit %d -> %d MiB", live.NsMemLimit, req.NsMemLimit))
+	}
+	if req.NsStorageLimit != live.NsStorageLimit {
+		changes = append(changes, fmt.Sprintf("namespace storage limit %d -> %d MiB", live.NsStorageLimit, req.NsStorageLimit))
+	}
+	if len(changes) == 0 {
+		return nil, nil, nil
+	}
+
+	return req, changes, nil
+}
+
+// RunAppApply creates or updates the apps of a manifest.
+func RunAppApply(c *CmdConfig) error {
+
+	file, err := c.Ankr.GetString(c.NS, types.ArgFileSlug)
+	if err != nil {
+		return err
+	}
+
+	dryRun, err := c.Ankr.GetBool(c.NS, types.ArgDryRunSlug)
+	if err != nil {
+		return err
+	}
+
+	m, err := readManifest(file)
+	if err != nil {
+		return err
+	}
+
+	appClient, err := c.AppMgr()
+	if err != nil {
+		return err
+	}
+
+	plans, err := fetchApplyPlans(appClient, m)
+	if err != nil {
+		return err
+	}
+
+	applier := &manifestApplier{client: appClient, created: map[*gwtaskmgr.CreateNamespaceRequest]string{}}
+
+	items := []displayers.ApplyResultItem{}
+	failed := 0
+	for _, p := range plans {
+		item := displayers.ApplyResultItem{
+			Name:    p.App.Name,
+			Action:  p.Action,
+			AppID:   p.AppID,
+			Changes: p.Changes,
+			Result:  "ok",
+		}
+
+		var applyErr error
+		switch {
+		case p.err != nil:
+			applyErr = p.err
+		case dryRun:
+			item.Result = "dry run"
+		default:
+			applyErr = applier.apply(p)
+			item.AppID = p.AppID
+		}
+
+		if applyErr != nil {
+			item.Result = "failed"
+			item.Error = applyErr.Error()
+			failed++
+		}
+
+		items = append(items, item)
+	}
+
+	if err := c.Display(&displayers.ApplyResult{Items: items}); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d app(s) failed to apply", failed, len(items))
+	}
+
+	return nil
+}
+
+// fetchApplyPlans plans the manifest against the live apps and namespaces.
+func fetchApplyPlans(appClient gwtaskmgr.AppMgrClient, m *Manifest) ([]*applyPlan, error) {
+	apps, err := appClient.AppList(context.Background(), &common_proto.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+	}
+
+	namespaces, err := appClient.NamespaceList(context.Background(), &common_proto.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+	}
+
+	return planApply(m, apps.AppReports, namespaces.NsReports), nil
+}
+
+// manifestApplier carries out apply plans, creating each new namespace once.
+type manifestApplier struct {
+	client  gwtaskmgr.AppMgrClient
+	created map[*gwtaskmgr.CreateNamespaceRequest]string
+}
+
+func (a *manifestApplier) apply(p *applyPlan) error {
+	ctx := context.Background()
+
+	if p.nsUpdate != nil {
+		if _, err := a.client.UpdateNamespace(ctx, p.nsUpdate); err != nil {
+			return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+		}
+	}
+
+	if p.Action == applyUnchanged || (p.Action == applyUpdate && p.chartVer == "") {
+		return nil
+	}
+
+	target, err := p.App.target()
+	if err != nil {
+		return err
+	}
+
+	if len(p.App.Values) > 0 {
+		if err := a.saveValues(ctx, p.App, target); err != nil {
+			return err
+		}
+	}
+
+	if p.Action == applyUpdate {
+		_, err := a.client.UpdateApp(ctx, &gwtaskmgr.UpdateAppRequest{AppId: p.AppID, ChartVer: p.chartVer})
+		if err != nil {
+			return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+		}
+		return nil
+	}
+
+	nsID := p.nsID
+	if p.newNamespace != nil {
+		nsID = a.created[p.newNamespace]
+		if nsID == "" {
+			rsp, err := a.client.CreateNamespace(ctx, p.newNamespace)
+			if err != nil {
+				return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+			}
+			nsID = rsp.NsId
+			a.created[p.newNamespace] = nsID
+		}
+	}
+
+	rsp, err := a.client.CreateApp(ctx, &gwtaskmgr.CreateAppRequest{
+		AppName: p.App.Name,
+		Chart: &gwtaskmgr.Chart{
+			ChartName: target.Name,
+			ChartRepo: target.Repo,
+			ChartVer:  target.Version,
+		},
+		NsId: nsID,
+	})
+	if err != nil {
+		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+	}
+	p.AppID = rsp.AppId
+
+	return nil
+}
+
+// saveValues saves the chart of app with its values merged over the chart
+// defaults as target, unless an earlier apply saved it already.
+func (a *manifestApplier) saveValues(ctx context.Context, app AppManifest, target ChartRef) error {
+	merged, err := appValues(ctx, a.client, app)
+	if err != nil {
+		return err
+	}
+
+	// the hub reports a chart that does not exist as an error
+	if saved, err := chartValues(ctx, a.client, target); err == nil {
+		if !valuesEqual(saved, merged) {
+			return fmt.Errorf("chart %s/%s@%s already exists with other values", target.Repo, target.Name, target.Version)
+		}
+		return nil
+	}
+
+	values, err := yaml.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("unable to encode values of app %s: %v", app.Name, err)
+	}
+
+	_, err = a.client.SaveAsChart(ctx, &gwtaskmgr.SaveAsChartRequest{
+		Source: &gwtaskmgr.Source{
+			ChartName: app.Chart.Name,
+			ChartRepo: app.Chart.Repo,
+			ChartVer:  app.Chart.Version,
+		},
+		Destination: &gwtaskmgr.Destination{
+			SaveasName: target.Name,
+			SaveasVer:  target.Version,
+		},
+		ValuesYaml: values,
+	})
+	if err != nil {
+		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"testing"
+
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanApply(t *testing.T) {
+	namespaces := []*common_proto.NamespaceReport{
+		{Namespace: &common_proto.Namespace{NsId: "ns-1", NsName: "team-a",
+			NsCpuLimit: 1000, NsMemLimit: 2048, NsStorageLimit: 10240}},
+	}
+	apps := []*common_proto.AppReport{
+		{AppDeployment: &common_proto.AppDeployment{AppId: "app-1", AppName: "web",
+			Namespace:   namespaces[0].Namespace,
+			ChartDetail: &common_proto.ChartDetail{ChartName: "wordpress", ChartRepo: "stable", ChartVer: "5.6.0"}}},
+		{AppDeployment: &common_proto.AppDeployment{AppId: "app-2", AppName: "db",
+			Namespace:   namespaces[0].Namespace,
+			ChartDetail: &common_proto.ChartDetail{ChartName: "mysql", ChartRepo: "stable", ChartVer: "1.0.0"}}},
+	}
+
+	m := &Manifest{Apps: []AppManifest{
+		{Name: "web", Chart: ChartRef{Name: "wordpress", Repo: "stable", Version: "5.7.0"},
+			Namespace: NamespaceRef{Name: "team-a", CpuLimit: "2"}},
+		{Name: "db", Chart: ChartRef{Name: "mysql", Repo: "stable", Version: "1.0.0"},
+			Namespace: NamespaceRef{ID: "ns-1"}},
+		{Name: "cache", Chart: ChartRef{Name: "redis", Repo: "stable", Version: "3.0.0"},
+			Namespace: NamespaceRef{Name: "team-b", CpuLimit: "1", MemLimit: "1", StorageLimit: "5"}},
+		{Name: "queue", Chart: ChartRef{Name: "rabbitmq", Repo: "stable", Version: "1.0.0"},
+			Namespace: NamespaceRef{Name: "team-c"}},
+		{Name: "api", Chart: ChartRef{Name: "api", Repo: "stable", Version: "1.0.0"},
+			Namespace: NamespaceRef{Name: "team-b", CpuLimit: "1", MemLimit: "1", StorageLimit: "5"},
+			Values:    map[string]interface{}{"replicas": 2}},
+	}}
+
+	plans := planApply(m, apps, namespaces)
+	assert.Len(t, plans, 5)
+
+	web := plans[0]
+	assert.NoError(t, web.err)
+	assert.Equal(t, applyUpdate, web.Action)
+	assert.Equal(t, "app-1", web.AppID)
+	assert.Equal(t, "5.7.0", web.chartVer)
+	if assert.NotNil(t, web.nsUpdate) {
+		assert.Equal(t, uint32(2000), web.nsUpdate.NsCpuLimit)
+		assert.Equal(t, uint32(2048), web.nsUpdate.NsMemLimit)
+	}
+
+	db := plans[1]
+	assert.NoError(t, db.err)
+	assert.Equal(t, applyUnchanged, db.Action)
+	assert.Empty(t, db.Changes)
+
+	cache := plans[2]
+	assert.NoError(t, cache.err)
+	assert.Equal(t, applyCreate, cache.Action)
+	assert.NotNil(t, cache.newNamespace)
+
+	queue := plans[3]
+	assert.Error(t, queue.err)
+
+	api := plans[4]
+	assert.NoError(t, api.err)
+	assert.Equal(t, applyCreate, api.Action)
+	assert.True(t, api.newNamespace == cache.newNamespace, "expected the new namespace to be shared")
+	target, err := api.App.target()
+	assert.NoError(t, err)
+	assert.Equal(t, "api", target.Name)
+	assert.Equal(t, userChartRepo, target.Repo)
+	assert.Regexp(t, `^1\.0\.0-values-[0-9a-f]{8}$`, target.Version)
+}
+
+func TestPlanApplyValues(t *testing.T) {
+	ns := &common_proto.Namespace{NsId: "ns-1", NsName: "team-a"}
+	namespaces := []*common_proto.NamespaceReport{{Namespace: ns}}
+
+	web := AppManifest{Name: "web", Chart: ChartRef{Name: "wordpress", Repo: "stable", Version: "5.7.0"},
+		Namespace: NamespaceRef{ID: "ns-1"}, Values: map[string]interface{}{"replicas": 2}}
+	target, err := web.target()
+	assert.NoError(t, err)
+
+	changed := web
+	changed.Values = map[string]interface{}{"replicas": 3}
+	changedTarget, err := changed.target()
+	assert.NoError(t, err)
+	assert.NotEqual(t, target.Version, changedTarget.Version)
+
+	live := func(version string) []*common_proto.AppReport {
+		return []*common_proto.AppReport{
+			{AppDeployment: &common_proto.AppDeployment{AppId: "app-1", AppName: "web", Namespace: ns,
+				ChartDetail: &common_proto.ChartDetail{ChartName: "web", ChartRepo: userChartRepo, ChartVer: version}}},
+		}
+	}
+
+	// same values at the same version
+	plans := planApply(&Manifest{Apps: []AppManifest{web}}, live(target.Version), namespaces)
+	assert.NoError(t, plans[0].err)
+	assert.Equal(t, applyUnchanged, plans[0].Action)
+
+	// only the values changed
+	plans = planApply(&Manifest{Apps: []AppManifest{changed}}, live(target.Version), namespaces)
+	assert.NoError(t, plans[0].err)
+	assert.Equal(t, applyUpdate, plans[0].Action)
+	assert.Equal(t, changedTarget.Version, plans[0].chartVer)
+	assert.Equal(t, []string{"values"}, plans[0].Changes)
+
+	// only the chart version changed
+	upgraded := web
+	upgraded.Chart.Version = "5.8.0"
+	upgradedTarget, err := upgraded.target()
+	assert.NoError(t, err)
+	plans = planApply(&Manifest{Apps: []AppManifest{upgraded}}, live(target.Version), namespaces)
+	assert.Equal(t, applyUpdate, plans[0].Action)
+	assert.Equal(t, upgradedTarget.Version, plans[0].chartVer)
+	assert.Equal(t, []string{"chart version 5.7.0 -> 5.8.0"}, plans[0].Changes)
+}
+
+func TestManifestApplierSaveValues(t *testing.T) {
+	web := AppManifest{Name: "web", Chart: ChartRef{Name: "wordpress", Repo: "stable", Version: "5.7.0"},
+		Values: map[string]interface{}{"replicas": 2, "image": map[string]interface{}{"tag": "5.1.1"}}}
+	target, err := web.target()
+	assert.NoError(t, err)
+
+	chart := "image:\n  repository: wordpress\n  tag: 5.1.0\nreplicas: 1\nservice: ClusterIP\n"
+	merged := "image:\n  repository: wordpress\n  tag: 5.1.1\nreplicas: 2\nservice: ClusterIP\n"
+	targetKey := userChartRepo + "/web@" + target.Version
+
+	appMgr := &fakeAppMgr{values: map[string]string{"stable/wordpress@5.7.0": chart}}
+	a := &manifestApplier{client: appMgr}
+	assert.NoError(t, a.saveValues(context.Background(), web, target))
+	if assert.Len(t, appMgr.saved, 1) {
+		saved := appMgr.saved[0]
+		assert.Equal(t, target.Version, saved.Destination.SaveasVer)
+		assert.Equal(t, "5.7.0", saved.Source.ChartVer)
+		assert.Equal(t, merged, string(saved.ValuesYaml), "the chart defaults are kept")
+	}
+
+	// saved already by an earlier apply
+	appMgr = &fakeAppMgr{values: map[string]string{"stable/wordpress@5.7.0": chart, targetKey: merged}}
+	a = &manifestApplier{client: appMgr}
+	assert.NoError(t, a.saveValues(context.Background(), web, target))
+	assert.Empty(t, appMgr.saved)
+
+	// saved with other values
+	appMgr = &fakeAppMgr{values: map[string]string{"stable/wordpress@5.7.0": chart, targetKey: "replicas: 2\n"}}
+	a = &manifestApplier{client: appMgr}
+	assert.Error(t, a.saveValues(context.Background(), web, target))
+	assert.Empty(t, appMgr.saved)
+}
@@ -0,0 +1,333 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Ankr-network/ankrctl/commands/displayers"
+	"github.com/Ankr-network/ankrctl/types"
+	gwusermgr "github.com/Ankr-network/dccn-common/protos/gateway/usermgr/v1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// defaultContext is the auth context whose settings live at the top
+	// level of the config file.
+	defaultContext = "default"
+
+	// authContextsKey holds every other auth context, keyed by name.
+	authContextsKey = "auth-contexts"
+
+	authResultKey = "AuthResult"
+	userDetailKey = "UserDetail"
+	userKey       = "User"
+)
+
+// contextNameRegexp matches the names allowed for an auth context. viper
+// lowercases keys and splits them on dots, so neither may appear in a name.
+var contextNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// savedAuthContexts holds the auth contexts replaced during this invocation,
+// see setAuthContexts.
+var savedAuthContexts map[string]interface{}
+
+// authCmd creates the auth command.
+func authCmd() *Command {
+	//DCCN-CLI auth
+	cmd := &Command{
+		Command: &cobra.Command{
+			Use:   "auth",
+			Short: "auth commands",
+			Long:  "auth is used to manage the hub identities saved by 'user login'",
+		},
+		DocCategories: []string{"auth"},
+		IsIndex:       true,
+	}
+
+	//DCCN-CLI auth context
+	cmdContext := &Command{
+		Command: &cobra.Command{
+			Use:     "context",
+			Aliases: []string{"ctx"},
+			Short:   "auth context commands",
+			Long: `context is used to manage named auth contexts. Each context keeps its own
+access token, user detail and hub url, saved by 'user login --context <name>'.`,
+		},
+		DocCategories: []string{"auth"},
+	}
+	cmd.AddCommand(cmdContext)
+
+	//DCCN-CLI auth context list
+	cmdAuthContextList := CmdBuilder(cmdContext, RunAuthContextList, "list", "list auth contexts",
+		Writer, aliasOpt("ls"), displayerType(&displayers.AuthContext{}), docCategories("auth"))
+	_ = cmdAuthContextList
+
+	//DCCN-CLI auth context use
+	cmdAuthContextUse := CmdBuilder(cmdContext, RunAuthContextUse, "use <context>",
+		"switch the active auth context", Writer, docCategories("auth"))
+	_ = cmdAuthContextUse
+
+	//DCCN-CLI auth context rename
+	cmdAuthContextRename := CmdBuilder(cmdContext, RunAuthContextRename, "rename <context> <new-context>",
+		"rename an auth context", Writer, aliasOpt("mv"), docCategories("auth"))
+	_ = cmdAuthContextRename
+
+	//DCCN-CLI auth context delete
+	cmdAuthContextDelete := CmdBuilder(cmdContext, RunAuthContextDelete, "delete <context>",
+		"delete an auth context", Writer, aliasOpt("rm"), docCategories("auth"))
+	AddBoolFlag(cmdAuthContextDelete, types.ArgForce, types.ArgShortForce, false, "Force auth context delete")
+
+	return cmd
+}
+
+// RunAuthContextList lists the auth contexts.
+func RunAuthContextList(c *CmdConfig) error {
+
+	current := currentContext()
+
+	list := []displayers.AuthContextInfo{}
+	for _, name := range contextNames() {
+		authResult := contextAuthResult(name)
+		user := contextUserDetail(name)
+
+		list = append(list, displayers.AuthContextInfo{
+			Name:     name,
+			Current:  name == current,
+			Email:    user.Email,
+			HubURL:   viper.GetString(contextKey(name, types.ArgHubURL)),
+			LoggedIn: authResult.AccessToken != "",
+		})
+	}
+
+	return c.Display(&displayers.AuthContext{Contexts: list})
+}
+
+// RunAuthContextUse makes an auth context the active one.
+func RunAuthContextUse(c *CmdConfig) error {
+
+	if len(c.Args) < 1 {
+		return types.NewMissingArgsErr(c.NS)
+	}
+
+	name := c.Args[0]
+	if !contextExists(name) {
+		return fmt.Errorf("auth context %q not found", name)
+	}
+
+	viper.Set(types.ArgContext, name)
+	Context = name
+	if err := writeConfig(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Switched to auth context %q.\n", name)
+
+	return nil
+}
+
+// RunAuthContextRename renames an auth context.
+func RunAuthContextRename(c *CmdConfig) error {
+
+	if len(c.Args) < 2 {
+		return types.NewMissingArgsErr(c.NS)
+	}
+
+	from, to := c.Args[0], c.Args[1]
+	if from == defaultContext || to == defaultContext {
+		return fmt.Errorf("the %q auth context cannot be renamed", defaultContext)
+	}
+	if !contextExists(from) {
+		return fmt.Errorf("auth context %q not found", from)
+	}
+	if contextExists(to) {
+		return fmt.Errorf("auth context %q already exists", to)
+	}
+	if err := validateContextName(to); err != nil {
+		return err
+	}
+
+	contexts := authContexts()
+	contexts[to] = contexts[from]
+	delete(contexts, from)
+	setAuthContexts(contexts)
+
+	if viper.GetString(types.ArgContext) == from {
+		viper.Set(types.ArgContext, to)
+	}
+	if err := writeConfig(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Auth context %q renamed to %q.\n", from, to)
+
+	return nil
+}
+
+// RunAuthContextDelete deletes an auth context. The default context cannot
+// be removed, deleting it only clears its saved credentials.
+func RunAuthContextDelete(c *CmdConfig) error {
+
+	force, err := c.Ankr.GetBool(c.NS, types.ArgForce)
+	if err != nil {
+		return err
+	}
+
+	if len(c.Args) < 1 {
+		return types.NewMissingArgsErr(c.NS)
+	}
+
+	name := c.Args[0]
+	if !contextExists(name) {
+		return fmt.Errorf("auth context %q not found", name)
+	}
+
+	if !force && AskForConfirm(fmt.Sprintf("Are you sure you want to delete auth context %q (y/N) ? ", name)) != nil {
+		return fmt.Errorf("Operation aborted")
+	}
+
+	if name == defaultContext {
+		viper.Set(authResultKey, "")
+		viper.Set(userDetailKey, "")
+		viper.Set(userKey, "")
+	} else {
+		contexts := authContexts()
+		delete(contexts, name)
+		setAuthContexts(contexts)
+	}
+
+	if viper.GetString(types.ArgContext) == name {
+		viper.Set(types.ArgContext, defaultContext)
+	}
+	if err := writeConfig(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Auth context %q deleted.\n", name)
+
+	return nil
+}
+
+// currentContext returns the name of the active auth context.
+func currentContext() string {
+	if Context != "" {
+		return Context
+	}
+
+	if name := viper.GetString(types.ArgContext); name != "" {
+		return name
+	}
+
+	return defaultContext
+}
+
+// validateContextName checks that name can be used as an auth context.
+func validateContextName(name string) error {
+	if !contextNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid auth context name %q, use lowercase letters, digits, '-' and '_'", name)
+	}
+
+	return nil
+}
+
+// contextKey returns the config key holding key for the auth context name.
+func contextKey(name, key string) string {
+	if name == defaultContext {
+		return key
+	}
+
+	return fmt.Sprintf("%s.%s.%s", authContextsKey, name, strings.ToLower(key))
+}
+
+// contextNames returns the names of all auth contexts, default first.
+func contextNames() []string {
+	names := []string{}
+	for name := range authContexts() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return append([]string{defaultContext}, names...)
+}
+
+// contextExists reports whether the auth context name has been saved.
+func contextExists(name string) bool {
+	if name == defaultContext {
+		return true
+	}
+
+	_, ok := authContexts()[name]
+	return ok
+}
+
+// authContexts returns the named auth contexts, keyed by name.
+func authContexts() map[string]interface{} {
+	contexts := map[string]interface{}{}
+	for name, v := range viper.GetStringMap(authContextsKey) {
+		contexts[name] = v
+	}
+
+	return contexts
+}
+
+// setAuthContexts replaces the named auth contexts. When the config is
+// written viper merges keys read from the config file back into a replaced
+// map, so writeConfig uses the saved map as a whole instead.
+func setAuthContexts(contexts map[string]interface{}) {
+	viper.Set(authContextsKey, contexts)
+	savedAuthContexts = contexts
+}
+
+// setContextValue sets key for the auth context name.
+func setContextValue(name, key string, value interface{}) {
+	if name == defaultContext {
+		viper.Set(key, value)
+		return
+	}
+
+	contexts := authContexts()
+	entry, ok := contexts[name].(map[string]interface{})
+	if !ok {
+		entry = map[string]interface{}{}
+	}
+
+	updated := map[string]interface{}{}
+	for k, v := range entry {
+		updated[k] = v
+	}
+	updated[strings.ToLower(key)] = value
+
+	contexts[name] = updated
+	setAuthContexts(contexts)
+}
+
+// contextAuthResult returns the tokens saved for the auth context name.
+func contextAuthResult(name string) gwusermgr.AuthenticationResult {
+	authResult := gwusermgr.AuthenticationResult{}
+	viper.UnmarshalKey(contextKey(name, authResultKey), &authResult)
+
+	return authResult
+}
+
+// contextUserDetail returns the user saved for the auth context name.
+func contextUserDetail(name string) gwusermgr.User {
+	user := gwusermgr.User{}
+	viper.UnmarshalKey(contextKey(name, userDetailKey), &user)
+
+	return user
+}
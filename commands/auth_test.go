@@ -0,0 +1,160 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ankr-network/ankrctl/types"
+	gwusermgr "github.com/Ankr-network/dccn-common/protos/gateway/usermgr/v1"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAuthConfig = `context: staging
+AuthResult:
+  accesstoken: default-token
+auth-contexts:
+  staging:
+    hub-url: hub.staging.ankr.network:50051
+    authresult:
+      accesstoken: staging-token
+  dev:
+    authresult:
+      accesstoken: dev-token
+`
+
+// withAuthConfig runs fn against a temporary config file holding config and
+// returns the config written back to it.
+func withAuthConfig(t *testing.T, config string, fn func()) *viper.Viper {
+	dir, err := ioutil.TempDir("", "ankrctl-auth")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "config.yaml")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(config), 0600))
+
+	oldCfgFile, oldContext := cfgFile, Context
+	defer func() {
+		cfgFile, Context, savedAuthContexts = oldCfgFile, oldContext, nil
+		viper.Reset()
+	}()
+
+	cfgFile, Context, savedAuthContexts = file, "", nil
+	viper.Reset()
+	viper.SetConfigType("yaml")
+	viper.SetConfigFile(file)
+	assert.NoError(t, viper.ReadInConfig())
+
+	fn()
+
+	saved := viper.New()
+	saved.SetConfigType("yaml")
+	saved.SetConfigFile(file)
+	assert.NoError(t, saved.ReadInConfig())
+
+	return saved
+}
+
+func TestContextKey(t *testing.T) {
+	assert.Equal(t, "AuthResult", contextKey(defaultContext, authResultKey))
+	assert.Equal(t, "auth-contexts.staging.authresult", contextKey("staging", authResultKey))
+	assert.Equal(t, "auth-contexts.staging.hub-url", contextKey("staging", types.ArgHubURL))
+}
+
+func TestSetContextValue(t *testing.T) {
+	saved := withAuthConfig(t, testAuthConfig, func() {
+		assert.Equal(t, "staging", currentContext())
+		assert.Equal(t, []string{"default", "dev", "staging"}, contextNames())
+		assert.Equal(t, "staging-token", contextAuthResult("staging").AccessToken)
+		assert.Equal(t, "default-token", contextAuthResult(defaultContext).AccessToken)
+
+		setContextValue("staging", authResultKey, gwusermgr.AuthenticationResult{AccessToken: "new-token"})
+		setContextValue("prod", types.ArgHubURL, "hub.ankr.network:50051")
+		setContextValue(defaultContext, authResultKey, gwusermgr.AuthenticationResult{AccessToken: "new-default"})
+
+		assert.Equal(t, "new-token", contextAuthResult("staging").AccessToken)
+		assert.Equal(t, "new-default", contextAuthResult(defaultContext).AccessToken)
+		assert.True(t, contextExists("prod"))
+		assert.NoError(t, writeConfig())
+	})
+
+	assert.Equal(t, "new-token", saved.GetString("auth-contexts.staging.authresult.accesstoken"))
+	assert.Equal(t, "hub.staging.ankr.network:50051", saved.GetString("auth-contexts.staging.hub-url"),
+		"other values of the context are kept")
+	assert.Equal(t, "hub.ankr.network:50051", saved.GetString("auth-contexts.prod.hub-url"))
+	assert.Equal(t, "dev-token", saved.GetString("auth-contexts.dev.authresult.accesstoken"))
+	assert.Equal(t, "new-default", saved.GetString("authresult.accesstoken"))
+}
+
+func TestRunAuthContextUse(t *testing.T) {
+	saved := withAuthConfig(t, testAuthConfig, func() {
+		err := RunAuthContextUse(&CmdConfig{Ankr: testConfig{}, Args: []string{"prod"}})
+		assert.EqualError(t, err, `auth context "prod" not found`)
+
+		assert.NoError(t, RunAuthContextUse(&CmdConfig{Ankr: testConfig{}, Args: []string{"dev"}}))
+		assert.Equal(t, "dev", currentContext())
+	})
+
+	assert.Equal(t, "dev", saved.GetString(types.ArgContext))
+}
+
+func TestRunAuthContextRename(t *testing.T) {
+	rename := func(from, to string) error {
+		return RunAuthContextRename(&CmdConfig{Ankr: testConfig{}, Args: []string{from, to}})
+	}
+
+	saved := withAuthConfig(t, testAuthConfig, func() {
+		assert.Error(t, rename(defaultContext, "main"))
+		assert.Error(t, rename("dev", defaultContext))
+		assert.EqualError(t, rename("prod", "main"), `auth context "prod" not found`)
+		assert.EqualError(t, rename("dev", "staging"), `auth context "staging" already exists`)
+		assert.Error(t, rename("dev", "Dev.1"))
+
+		assert.NoError(t, rename("staging", "stage"))
+		assert.Equal(t, []string{"default", "dev", "stage"}, contextNames())
+		assert.Equal(t, "stage", viper.GetString(types.ArgContext), "the current context follows the rename")
+	})
+
+	assert.Equal(t, "stage", saved.GetString(types.ArgContext))
+	assert.False(t, saved.IsSet("auth-contexts.staging"))
+	assert.Equal(t, "staging-token", saved.GetString("auth-contexts.stage.authresult.accesstoken"))
+	assert.Equal(t, "hub.staging.ankr.network:50051", saved.GetString("auth-contexts.stage.hub-url"))
+}
+
+func TestRunAuthContextDelete(t *testing.T) {
+	remove := func(name string) error {
+		return RunAuthContextDelete(&CmdConfig{Ankr: testConfig{types.ArgForce: true}, Args: []string{name}})
+	}
+
+	saved := withAuthConfig(t, testAuthConfig, func() {
+		assert.EqualError(t, remove("prod"), `auth context "prod" not found`)
+
+		assert.NoError(t, remove("staging"))
+		assert.Equal(t, []string{"default", "dev"}, contextNames())
+		assert.Equal(t, defaultContext, viper.GetString(types.ArgContext), "deleting the current context falls back to default")
+
+		assert.NoError(t, remove(defaultContext))
+		assert.True(t, contextExists(defaultContext), "the default context is only logged out")
+		assert.Empty(t, contextAuthResult(defaultContext).AccessToken)
+	})
+
+	assert.Equal(t, defaultContext, saved.GetString(types.ArgContext))
+	assert.False(t, saved.IsSet("auth-contexts.staging"))
+	assert.Equal(t, "dev-token", saved.GetString("auth-contexts.dev.authresult.accesstoken"))
+	assert.Empty(t, saved.GetString("authresult.accesstoken"))
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Ankr-network/ankrctl/commands/displayers"
+	"github.com/Ankr-network/ankrctl/types"
+	"google.golang.org/grpc/status"
+)
+
+// defaultConcurrency is how many items of a batch run at once.
+const defaultConcurrency = 4
+
+// batchOp performs an operation on one argument of a command and returns
+// the ID of the resource it affected.
+type batchOp func(arg string) (string, error)
+
+// batch runs an operation on every argument of a multi-argument command.
+type batch struct {
+	// Action names the operation in the summary, such as create.
+	Action string
+
+	// Concurrency bounds how many items run at once.
+	Concurrency int
+
+	// ContinueOnError runs the remaining items after one has failed,
+	// instead of skipping them.
+	ContinueOnError bool
+}
+
+// batchFlags adds the flags controlling a batch to cmd.
+func batchFlags(cmd *Command) {
+	AddIntFlag(cmd, types.ArgConcurrencySlug, "", defaultConcurrency, "How many items to process at once")
+	AddBoolFlag(cmd, types.ArgContinueOnErrorSlug, "", false, "Process the remaining items after one fails")
+}
+
+// newBatch reads the batch flags of c.
+func newBatch(c *CmdConfig, action string) (*batch, error) {
+	concurrency, err := c.Ankr.GetInt(c.NS, types.ArgConcurrencySlug)
+	if err != nil {
+		return nil, err
+	}
+	if concurrency < 1 {
+		return nil, fmt.Errorf("--%s must be at least 1", types.ArgConcurrencySlug)
+	}
+
+	continueOnError, err := c.Ankr.GetBool(c.NS, types.ArgContinueOnErrorSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	return &batch{Action: action, Concurrency: concurrency, ContinueOnError: continueOnError}, nil
+}
+
+// run performs op on every arg, at most Concurrency at a time, and returns
+// the result of each in the order of args. Once an item has failed, items
+// that have not started yet are skipped unless ContinueOnError is set. Items
+// already running are left to finish, as the hub may have acted on them.
+func (b *batch) run(args []string, op batchOp) []displayers.BatchResultInfo {
+	results := make([]displayers.BatchResultInfo, len(args))
+
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, b.Concurrency)
+	for i, arg := range args {
+		results[i] = displayers.BatchResultInfo{Item: arg, Action: b.Action}
+
+		sem <- struct{}{}
+		select {
+		case <-stop:
+			<-sem
+			results[i].Result = displayers.BatchSkipped
+			continue
+		default:
+		}
+
+		wg.Add(1)
+		go func(r *displayers.BatchResultInfo) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			id, err := op(r.Item)
+			if err != nil {
+				r.Result = displayers.BatchFailed
+				r.Error = batchError(err)
+				if !b.ContinueOnError {
+					stopOnce.Do(func() { close(stop) })
+				}
+				return
+			}
+
+			r.ID = id
+			r.Result = displayers.BatchSucceeded
+		}(&results[i])
+	}
+	wg.Wait()
+
+	return results
+}
+
+// batchError describes err, adding the status code of hub errors.
+func batchError(err error) string {
+	if s, ok := status.FromError(err); ok && s != nil {
+		return fmt.Sprintf("Status Code: %s  Message: %s", s.Code(), s.Message())
+	}
+
+	return err.Error()
+}
+
+// batchSucceeded returns the IDs affected by the items that succeeded.
+func batchSucceeded(results []displayers.BatchResultInfo) []string {
+	var ids []string
+	for _, r := range results {
+		if r.Result == displayers.BatchSucceeded {
+			ids = append(ids, r.ID)
+		}
+	}
+
+	return ids
+}
+
+// displayBatch shows the summary of a batch. A batch where every item
+// failed is an error, one where only some failed ends the command with
+// exitPartialFailure.
+func (c *CmdConfig) displayBatch(results []displayers.BatchResultInfo) error {
+	if err := c.Display(&displayers.BatchResult{Results: results}); err != nil {
+		return err
+	}
+
+	succeeded := len(batchSucceeded(results))
+	switch {
+	case succeeded == len(results):
+		return nil
+	case succeeded == 0:
+		return fmt.Errorf("%d of %d item(s) failed", len(results), len(results))
+	default:
+		return &exitError{code: exitPartialFailure}
+	}
+}
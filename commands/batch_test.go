@@ -0,0 +1,77 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ankr-network/ankrctl/commands/displayers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchRun(t *testing.T) {
+	var running, peak int32
+	op := func(arg string) (string, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		if arg == "bad" {
+			return "", errors.New("boom")
+		}
+		return "id-" + arg, nil
+	}
+
+	b := &batch{Action: "create", Concurrency: 2, ContinueOnError: true}
+	results := b.run([]string{"a", "bad", "c", "d"}, op)
+	assert.Len(t, results, 4)
+	assert.Equal(t, "id-a", results[0].ID)
+	assert.Equal(t, displayers.BatchFailed, results[1].Result)
+	assert.Equal(t, "boom", results[1].Error)
+	assert.Equal(t, []string{"id-a", "id-c", "id-d"}, batchSucceeded(results))
+	assert.True(t, peak <= 2, "ran %d items at once", peak)
+
+	b = &batch{Action: "create", Concurrency: 1}
+	results = b.run([]string{"a", "bad", "c"}, op)
+	assert.Equal(t, displayers.BatchSucceeded, results[0].Result)
+	assert.Equal(t, displayers.BatchFailed, results[1].Result)
+	assert.Equal(t, displayers.BatchSkipped, results[2].Result)
+
+	// an item already running when another fails is not interrupted
+	failed := make(chan struct{})
+	op = func(arg string) (string, error) {
+		switch arg {
+		case "slow":
+			<-failed
+			time.Sleep(50 * time.Millisecond)
+		case "bad":
+			close(failed)
+			return "", errors.New("boom")
+		}
+		return "id-" + arg, nil
+	}
+
+	b = &batch{Action: "create", Concurrency: 2}
+	results = b.run([]string{"slow", "bad"}, op)
+	assert.Equal(t, displayers.BatchSucceeded, results[0].Result)
+	assert.Equal(t, displayers.BatchFailed, results[1].Result)
+}
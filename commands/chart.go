@@ -17,26 +17,16 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/Ankr-network/ankrctl/types"
-	"io/ioutil"
-	"log"
 	"os"
-	"time"
 
-	"github.com/spf13/viper"
 	"k8s.io/helm/pkg/chartutil"
 
 	"github.com/Ankr-network/ankrctl/commands/displayers"
-	"github.com/gobwas/glob"
 	"github.com/spf13/cobra"
 
 	"context"
 
-	ankr_const "github.com/Ankr-network/dccn-common"
-	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
-	gwusermgr "github.com/Ankr-network/dccn-common/protos/gateway/usermgr/v1"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -58,18 +48,37 @@ func chartCmd() *Command {
 	cmdRunChartUpload := CmdBuilder(cmd, RunChartUpload, "upload <upload-name>", "create chart", Writer,
 		aliasOpt("cr"), docCategories("chart"))
 	AddStringFlag(cmdRunChartUpload, types.ArgUploadVersionSlug, "", "", "Chart Version", requiredOpt())
-	AddStringFlag(cmdRunChartUpload, types.ArgUploadFileSlug, "", "", "Chart File", requiredOpt())
+	AddStringFlag(cmdRunChartUpload, types.ArgUploadFileSlug, "", "", "Chart archive (.tgz) or chart directory", requiredOpt())
+	AddBoolFlag(cmdRunChartUpload, types.ArgDryRunSlug, "", false, "Check and package the chart without uploading it")
 
 	//DCCN-CLI chart list
 	cmdRunChartList := CmdBuilder(cmd, RunChartList, "list [GLOB]", "list chart", Writer,
 		aliasOpt("ls"), displayerType(&displayers.Chart{}), docCategories("chart"))
 	AddStringFlag(cmdRunChartList, types.ArgListRepoSlug, "", "", "List Repo")
+	AddBoolFlag(cmdRunChartList, types.ArgVersionsSlug, "", false, "List every version of the charts, not just the latest")
+	filterFlags(cmdRunChartList)
 
 	//DCCN-CLI chart detail
 	cmdRunChartDetail := CmdBuilder(cmd, RunChartDetail, "detail <detail-name>", "get chart details", Writer,
-		aliasOpt("dt"), docCategories("chart"))
+		aliasOpt("dt"), displayerType(&displayers.ChartDetail{}), docCategories("chart"))
 	AddStringFlag(cmdRunChartDetail, types.ArgDetailRepoSlug, "", "", "Detail Repo", requiredOpt())
-	AddStringFlag(cmdRunChartDetail, types.ArgShowVersionSlug, "", "", "Show Version", requiredOpt())
+	AddStringFlag(cmdRunChartDetail, types.ArgShowVersionSlug, "", "", "Show Version, or a constraint such as ^1.2 or latest", requiredOpt())
+
+	//DCCN-CLI chart diff
+	cmdRunChartDiff := CmdBuilder(cmd, RunChartDiff, "diff <chart-name>", "show how the values of two chart versions differ",
+		Writer, aliasOpt("df"), displayerType(&displayers.ChartDiff{}), docCategories("chart"))
+	AddStringFlag(cmdRunChartDiff, types.ArgFromSlug, "", "", "Chart version to compare from, as repo@version", requiredOpt())
+	AddStringFlag(cmdRunChartDiff, types.ArgToSlug, "", "", "Chart version to compare to, as repo@version", requiredOpt())
+
+	//DCCN-CLI chart template
+	cmdRunChartTemplate := CmdBuilder(cmd, RunChartTemplate, "template <chart-name>",
+		"render the manifests of a chart locally", Writer, aliasOpt("tpl"), docCategories("chart"))
+	AddStringFlag(cmdRunChartTemplate, types.ArgRepoSlug, "", "", "Chart repo, leave out with --version for a local chart")
+	AddStringFlag(cmdRunChartTemplate, types.ArgVersionSlug, "", "", "Chart version, or a constraint such as ^1.2 or latest")
+	AddStringFlag(cmdRunChartTemplate, types.ArgAppNameSlug, "", defaultReleaseName, "App name the chart is rendered for")
+	AddStringFlag(cmdRunChartTemplate, types.ArgNsNameSlug, "", defaultReleaseNamespace, "Namespace the chart is rendered for")
+	AddStringFlag(cmdRunChartTemplate, types.ArgOutputDirSlug, "", "", "Write each manifest to this directory instead of stdout")
+	valuesFlags(cmdRunChartTemplate)
 
 	//DCCN-CLI chart update
 	cmdRunChartSaveas := CmdBuilder(cmd, RunChartSaveas, "saveas <saveas-name>", "saveas chart", Writer,
@@ -78,13 +87,15 @@ func chartCmd() *Command {
 	AddStringFlag(cmdRunChartSaveas, types.ArgSourceVersionSlug, "", "", "Source Version", requiredOpt())
 	AddStringFlag(cmdRunChartSaveas, types.ArgSourceNameSlug, "", "", "Source Name", requiredOpt())
 	AddStringFlag(cmdRunChartSaveas, types.ArgSaveasVersionSlug, "", "", "SaveAs Version", requiredOpt())
-	AddStringFlag(cmdRunChartSaveas, types.ArgValuesYamlSlug, "", "", "Values Yaml File", requiredOpt())
+	AddStringFlag(cmdRunChartSaveas, types.ArgValuesYamlSlug, "", "", "Values Yaml File, merged before --values")
+	AddBoolFlag(cmdRunChartSaveas, types.ArgBaseValuesSlug, "", false, "Merge the values onto the values.yaml of the source chart")
+	valuesFlags(cmdRunChartSaveas)
 
 	//DCCN-CLI chart download
 	cmdRunChartDownload := CmdBuilder(cmd, RunChartDownload, "download <download-name>",
 		"download chart", Writer, aliasOpt("dl"), docCategories("chart"))
 	AddStringFlag(cmdRunChartDownload, types.ArgDownloadRepoSlug, "", "", "Download Repo", requiredOpt())
-	AddStringFlag(cmdRunChartDownload, types.ArgDownloadVersionSlug, "", "", "Download Version", requiredOpt())
+	AddStringFlag(cmdRunChartDownload, types.ArgDownloadVersionSlug, "", "", "Download Version, or a constraint such as ^1.2 or latest", requiredOpt())
 
 	//DCCN-CLI chart delete
 	cmdRunChartDelete := CmdBuilder(cmd, RunChartDelete, "delete <delete-name>", "delete chart",
@@ -101,50 +112,43 @@ func RunChartUpload(c *CmdConfig) error {
 		return types.NewMissingArgsErr(c.NS)
 	}
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
+	uploadChartRequest := &gwtaskmgr.UploadChartRequest{}
+	uploadChartRequest.ChartName = c.Args[0]
 
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	var err error
+	uploadChartRequest.ChartVer, err = c.Ankr.GetString(c.NS, types.ArgUploadVersionSlug)
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-	defer conn.Close()
+	uploadChartRequest.ChartRepo = "user"
 
-	appClient := gwtaskmgr.NewAppMgrClient(conn)
+	file, err := c.Ankr.GetString(c.NS, types.ArgUploadFileSlug)
 	if err != nil {
 		return err
 	}
 
-	uploadChartRequest := &gwtaskmgr.UploadChartRequest{}
-	uploadChartRequest.ChartName = c.Args[0]
-
-	uploadChartRequest.ChartVer, err = c.Ankr.GetString(c.NS, types.ArgUploadVersionSlug)
+	dryRun, err := c.Ankr.GetBool(c.NS, types.ArgDryRunSlug)
 	if err != nil {
 		return err
 	}
-	uploadChartRequest.ChartRepo = "user"
 
-	file, err := c.Ankr.GetString(c.NS, types.ArgUploadFileSlug)
+	uploadChartRequest.ChartFile, err = loadUploadChart(file, uploadChartRequest.ChartName, uploadChartRequest.ChartVer)
 	if err != nil {
 		return err
 	}
-	uploadChartRequest.ChartFile, err = ioutil.ReadFile(file)
+
+	if dryRun {
+		fmt.Printf("Chart %s version %s is valid, %d bytes packaged.\n",
+			uploadChartRequest.ChartName, uploadChartRequest.ChartVer, len(uploadChartRequest.ChartFile))
+		return nil
+	}
+
+	appClient, err := c.AppMgr()
 	if err != nil {
 		return err
 	}
 
-	_, err = appClient.UploadChart(tokenctx, uploadChartRequest)
+	_, err = appClient.UploadChart(context.Background(), uploadChartRequest)
 	if err != nil {
 		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 	}
@@ -158,66 +162,58 @@ func RunChartUpload(c *CmdConfig) error {
 // RunChartList returns a list of chart.
 func RunChartList(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	matches := []glob.Glob{}
-	for _, globStr := range c.Args {
-		g, err := glob.Compile(globStr)
-		if err != nil {
-			return fmt.Errorf("unknown glob %q", globStr)
-		}
-
-		matches = append(matches, g)
+	appClient, err := c.AppMgr()
+	if err != nil {
+		return err
 	}
 
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	chartRepo, err := c.Ankr.GetString(c.NS, types.ArgListRepoSlug)
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-	defer conn.Close()
-	appClient := gwtaskmgr.NewAppMgrClient(conn)
 
-	chartRepo, err := c.Ankr.GetString(c.NS, types.ArgListRepoSlug)
+	versions, err := c.Ankr.GetBool(c.NS, types.ArgVersionsSlug)
 	if err != nil {
 		return err
 	}
-	r, err := appClient.ChartList(tokenctx, &gwtaskmgr.ChartListRequest{ChartRepo: chartRepo})
+
+	filter, err := newListFilter(c)
 	if err != nil {
-		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+		return err
 	}
-	var matchedList []*common_proto.Chart
-
-	for _, chart := range r.Charts {
-		var skip = true
-		if len(matches) == 0 {
-			skip = false
-		} else {
-			for _, m := range matches {
-				if m.Match(chart.ChartName) {
-					skip = false
-				}
-			}
+
+	return c.DisplayList(func() (displayers.Displayable, error) {
+		r, err := appClient.ChartList(context.Background(), &gwtaskmgr.ChartListRequest{ChartRepo: chartRepo})
+		if err != nil {
+			return nil, fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 		}
 
-		if !skip {
-			matchedList = append(matchedList, chart)
+		charts := &displayers.Chart{Charts: r.Charts}
+		if !versions {
+			return charts, nil
 		}
-	}
-	item := &displayers.Chart{Charts: matchedList}
-	return c.Display(item)
+
+		// the versions are only fetched for the charts the filter keeps
+		filter.prefilter(charts)
+
+		item := &displayers.ChartVersions{}
+		for _, ch := range charts.Charts {
+			detail, err := appClient.ChartDetail(context.Background(), &gwtaskmgr.ChartDetailRequest{
+				ChartName: ch.ChartName,
+				ChartRepo: ch.ChartRepo,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+			}
+
+			for _, v := range detail.ChartVersionDetails {
+				item.Versions = append(item.Versions, displayers.ChartVersionInfo{
+					Repo: ch.ChartRepo, Name: ch.ChartName, Version: v.ChartVer, AppVersion: v.ChartAppVer,
+				})
+			}
+		}
+		return item, nil
+	})
 }
 
 // RunChartDetail returns chart details.
@@ -226,27 +222,10 @@ func RunChartDetail(c *CmdConfig) error {
 		return types.NewMissingArgsErr(c.NS)
 	}
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	appClient, err := c.AppMgr()
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-	defer conn.Close()
-	appClient := gwtaskmgr.NewAppMgrClient(conn)
 
 	chartDetailRequest := &gwtaskmgr.ChartDetailRequest{ChartName: c.Args[0]}
 	chartDetailRequest.ChartRepo, err = c.Ankr.GetString(c.NS, types.ArgDetailRepoSlug)
@@ -257,20 +236,40 @@ func RunChartDetail(c *CmdConfig) error {
 	if err != nil {
 		return err
 	}
+	chartDetailRequest.ChartVer, err = resolveChartVersion(c, chartDetailRequest.ChartName,
+		chartDetailRequest.ChartRepo, chartDetailRequest.ChartVer)
+	if err != nil {
+		return err
+	}
 
-	r, err := appClient.ChartDetail(tokenctx, chartDetailRequest)
+	r, err := appClient.ChartDetail(context.Background(), chartDetailRequest)
 	if err != nil {
 		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 	}
-	if r.ChartVersionDetails != nil {
-		fmt.Printf("Repo: %s\tChart: %s \n", r.ChartRepo, r.ChartName)
-		fmt.Println("Version\t\tApp Version")
-		for _, versiondetail := range r.ChartVersionDetails {
-			fmt.Printf("%s\t\t%s\n", versiondetail.ChartVer, versiondetail.ChartAppVer)
-		}
+
+	item := &displayers.ChartDetail{Detail: displayers.ChartDetailInfo{
+		Repo:    r.ChartRepo,
+		Name:    r.ChartName,
+		Version: chartDetailRequest.ChartVer,
+		Readme:  fmt.Sprintf("%s", r.ReadmeMd),
+		Values:  fmt.Sprintf("%s", r.ValuesYaml),
+	}}
+	for _, versiondetail := range r.ChartVersionDetails {
+		item.Detail.Versions = append(item.Detail.Versions, displayers.ChartVersion{
+			Version:    versiondetail.ChartVer,
+			AppVersion: versiondetail.ChartAppVer,
+		})
+	}
+
+	if err := c.Display(item); err != nil {
+		return err
+	}
+
+	// the readme and values follow the table
+	if isTableOutput() {
+		fmt.Fprintf(c.Out, "\n++++++++++ Chart versions %s readme.md ++++++++++\n%s\n", item.Detail.Version, item.Detail.Readme)
+		fmt.Fprintf(c.Out, "\n++++++++++ Chart versions %s values.yaml ++++++++++\n%s\n", item.Detail.Version, item.Detail.Values)
 	}
-	fmt.Printf("\n++++++++++ Chart versions %s readme.md ++++++++++\n%s\n", chartDetailRequest.ChartVer, r.ReadmeMd)
-	fmt.Printf("\n++++++++++ Chart versions %s values.yaml ++++++++++\n%s\n", chartDetailRequest.ChartVer, r.ValuesYaml)
 	return nil
 }
 
@@ -280,27 +279,7 @@ func RunChartSaveas(c *CmdConfig) error {
 		return types.NewMissingArgsErr(c.NS)
 	}
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("did not connect: %v", err)
-	}
-	defer conn.Close()
-
-	appClient := gwtaskmgr.NewAppMgrClient(conn)
+	appClient, err := c.AppMgr()
 	if err != nil {
 		return err
 	}
@@ -336,17 +315,53 @@ func RunChartSaveas(c *CmdConfig) error {
 		ChartRepo: sourceRepo,
 		ChartVer:  sourceVer,
 	}
+	base := map[string]interface{}{}
+	baseValues, err := c.Ankr.GetBool(c.NS, types.ArgBaseValuesSlug)
+	if err != nil {
+		return err
+	}
+	if baseValues {
+		r, err := appClient.ChartDetail(context.Background(), &gwtaskmgr.ChartDetailRequest{
+			ChartName: sourceName,
+			ChartRepo: sourceRepo,
+			ChartVer:  sourceVer,
+		})
+		if err != nil {
+			return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+		}
+		base, err = chartutil.ReadValues([]byte(fmt.Sprintf("%s", r.ValuesYaml)))
+		if err != nil {
+			return fmt.Errorf("unable to parse values of %s: %v", sourceName, err)
+		}
+	}
+
 	file, err := c.Ankr.GetString(c.NS, types.ArgValuesYamlSlug)
 	if err != nil {
 		return err
 	}
+	if file != "" {
+		fileVals, err := readValuesFile(file)
+		if err != nil {
+			return err
+		}
+		mergeValues(base, fileVals)
+	}
 
-	saveasChartRequest.ValuesYaml, err = ioutil.ReadFile(file)
+	vals, err := readValues(c, base)
 	if err != nil {
 		return err
 	}
+	if len(vals) == 0 {
+		return fmt.Errorf("no values given, use --%s, --%s, --%s or --%s",
+			types.ArgValuesYamlSlug, types.ArgValuesSlug, types.ArgSetSlug, types.ArgBaseValuesSlug)
+	}
 
-	_, err = appClient.SaveAsChart(tokenctx, saveasChartRequest)
+	saveasChartRequest.ValuesYaml, err = valuesYaml(vals)
+	if err != nil {
+		return err
+	}
+
+	_, err = appClient.SaveAsChart(context.Background(), saveasChartRequest)
 	if err != nil {
 		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 	}
@@ -362,27 +377,7 @@ func RunChartDownload(c *CmdConfig) error {
 		return types.NewMissingArgsErr(c.NS)
 	}
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("did not connect: %v", err)
-	}
-	defer conn.Close()
-
-	appClient := gwtaskmgr.NewAppMgrClient(conn)
+	appClient, err := c.AppMgr()
 	if err != nil {
 		return err
 	}
@@ -399,7 +394,13 @@ func RunChartDownload(c *CmdConfig) error {
 		return err
 	}
 
-	rsp, err := appClient.DownloadChart(tokenctx, downloadChartRequest)
+	downloadChartRequest.ChartVer, err = resolveChartVersion(c, downloadChartRequest.ChartName,
+		downloadChartRequest.ChartRepo, downloadChartRequest.ChartVer)
+	if err != nil {
+		return err
+	}
+
+	rsp, err := appClient.DownloadChart(context.Background(), downloadChartRequest)
 	if err != nil {
 		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 	}
@@ -431,41 +432,23 @@ func RunChartDelete(c *CmdConfig) error {
 		return types.NewMissingArgsErr(c.NS)
 	}
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-
 	force, err := c.Ankr.GetBool(c.NS, types.ArgForce)
 	if err != nil {
 		return err
 	}
 
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
 	chartVersion, err := c.Ankr.GetString(c.NS, types.ArgDeleteVersionSlug)
 	if err != nil {
 		return err
 	}
 	if force || AskForConfirm(fmt.Sprintf("Are you sure you want to Delete chart %s version %s (y/N) ? ", c.Args[0], chartVersion)) == nil {
 
-		url := viper.GetString("hub-url")
-
-		conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+		appClient, err := c.AppMgr()
 		if err != nil {
-			log.Fatalf("Did not connect: %v", err)
+			return err
 		}
 
-		defer conn.Close()
-		appClient := gwtaskmgr.NewAppMgrClient(conn)
-
-		_, err = appClient.DeleteChart(tokenctx, &gwtaskmgr.DeleteChartRequest{
+		_, err = appClient.DeleteChart(context.Background(), &gwtaskmgr.DeleteChartRequest{
 			ChartName: c.Args[0],
 			ChartRepo: "user",
 			ChartVer:  chartVersion,
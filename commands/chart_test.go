@@ -0,0 +1,41 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Ankr-network/ankrctl/types"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunChartListVersions(t *testing.T) {
+	appMgr := &fakeAppMgr{charts: []*common_proto.Chart{
+		{ChartRepo: "stable", ChartName: "wordpress"},
+		{ChartRepo: "user", ChartName: "wordpress"},
+		{ChartRepo: "stable", ChartName: "mysql"},
+	}}
+
+	var out bytes.Buffer
+	c := fakeHubConfig(&out, appMgr, nil, "word*")
+	c.Ankr = testConfig{types.ArgVersionsSlug: true, types.ArgFieldSelectorSlug: "repo=stable,version!=1.0.0"}
+
+	assert.NoError(t, RunChartList(c))
+	assert.Equal(t, []string{"stable/wordpress"}, appMgr.chartDetails,
+		"versions are only fetched for the charts the filter keeps")
+	assert.Contains(t, out.String(), "1.1.0")
+	assert.NotContains(t, out.String(), "1.0.0")
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Ankr-network/ankrctl/commands/displayers"
+	"github.com/Ankr-network/ankrctl/types"
+	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
+	"google.golang.org/grpc/status"
+	"k8s.io/helm/pkg/chartutil"
+)
+
+// RunChartDiff shows how the values and app version of a chart differ
+// between two versions, possibly in different repos.
+func RunChartDiff(c *CmdConfig) error {
+	if len(c.Args) < 1 {
+		return types.NewMissingArgsErr(c.NS)
+	}
+	name := c.Args[0]
+
+	from, err := c.Ankr.GetString(c.NS, types.ArgFromSlug)
+	if err != nil {
+		return err
+	}
+
+	to, err := c.Ankr.GetString(c.NS, types.ArgToSlug)
+	if err != nil {
+		return err
+	}
+
+	fromRef, err := parseChartRef(from)
+	if err != nil {
+		return err
+	}
+
+	toRef, err := parseChartRef(to)
+	if err != nil {
+		return err
+	}
+
+	appClient, err := c.AppMgr()
+	if err != nil {
+		return err
+	}
+
+	item := &displayers.ChartDiff{Diff: displayers.ChartDiffInfo{Name: name}}
+	var values [2]map[string]interface{}
+	for i, ref := range []*displayers.ChartRef{&fromRef, &toRef} {
+		if ref.Version, err = resolveChartVersion(c, name, ref.Repo, ref.Version); err != nil {
+			return err
+		}
+
+		r, err := appClient.ChartDetail(context.Background(), &gwtaskmgr.ChartDetailRequest{
+			ChartName: name,
+			ChartRepo: ref.Repo,
+			ChartVer:  ref.Version,
+		})
+		if err != nil {
+			return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+		}
+
+		found := false
+		for _, v := range r.ChartVersionDetails {
+			if v.ChartVer == ref.Version {
+				ref.AppVersion, found = v.ChartAppVer, true
+			}
+		}
+		if !found {
+			return fmt.Errorf("chart %s has no version %s in %s", name, ref.Version, ref.Repo)
+		}
+
+		values[i], err = chartutil.ReadValues([]byte(fmt.Sprintf("%s", r.ValuesYaml)))
+		if err != nil {
+			return fmt.Errorf("unable to parse values of %s %s@%s: %v", name, ref.Repo, ref.Version, err)
+		}
+	}
+
+	item.Diff.From = fromRef
+	item.Diff.To = toRef
+	item.Diff.Values = diffValues("", values[0], values[1])
+
+	if err := c.Display(item); err != nil {
+		return err
+	}
+
+	// the app version follows the table
+	if isTableOutput() {
+		if fromRef.AppVersion == toRef.AppVersion {
+			fmt.Fprintf(c.Out, "\nApp version: %s (unchanged)\n", fromRef.AppVersion)
+		} else {
+			fmt.Fprintf(c.Out, "\nApp version: %s → %s\n", fromRef.AppVersion, toRef.AppVersion)
+		}
+	}
+
+	return nil
+}
+
+// parseChartRef parses a chart version given as repo@version.
+func parseChartRef(s string) (displayers.ChartRef, error) {
+	i := strings.LastIndex(s, "@")
+	if i <= 0 || i == len(s)-1 {
+		return displayers.ChartRef{}, fmt.Errorf("invalid chart version %q, want repo@version", s)
+	}
+
+	return displayers.ChartRef{Repo: s[:i], Version: s[i+1:]}, nil
+}
+
+// diffValues returns the keys added, removed or changed from a to b, sorted
+// by key. Maps present on both sides are compared key by key, prefix is the
+// path of a and b in the values.
+func diffValues(prefix string, a, b map[string]interface{}) []displayers.ChartValueChange {
+	keys := map[string]bool{}
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	changes := []displayers.ChartValueChange{}
+	for _, k := range sorted {
+		key := prefix + k
+		av, inA := a[k]
+		bv, inB := b[k]
+
+		switch {
+		case !inA:
+			changes = append(changes, displayers.ChartValueChange{
+				Key: key, Change: displayers.ValueAdded, To: formatValue(bv)})
+		case !inB:
+			changes = append(changes, displayers.ChartValueChange{
+				Key: key, Change: displayers.ValueRemoved, From: formatValue(av)})
+		default:
+			am, aIsMap := av.(map[string]interface{})
+			bm, bIsMap := bv.(map[string]interface{})
+			if aIsMap && bIsMap {
+				changes = append(changes, diffValues(key+".", am, bm)...)
+			} else if !reflect.DeepEqual(av, bv) {
+				changes = append(changes, displayers.ChartValueChange{
+					Key: key, Change: displayers.ValueChanged, From: formatValue(av), To: formatValue(bv)})
+			}
+		}
+	}
+
+	return changes
+}
+
+// formatValue returns v as shown in a diff: strings as they are, anything
+// else as compact JSON.
+func formatValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/Ankr-network/ankrctl/commands/displayers"
+	"github.com/Ankr-network/ankrctl/types"
+	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestParseChartRef(t *testing.T) {
+	ref, err := parseChartRef("stable@5.7.1")
+	assert.NoError(t, err)
+	assert.Equal(t, displayers.ChartRef{Repo: "stable", Version: "5.7.1"}, ref)
+
+	for _, s := range []string{"stable", "@5.7.1", "stable@"} {
+		_, err := parseChartRef(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestDiffValues(t *testing.T) {
+	a := map[string]interface{}{
+		"image":        map[string]interface{}{"repository": "wordpress", "tag": "5.1.0"},
+		"replicaCount": float64(1),
+		"persistence":  map[string]interface{}{"enabled": true},
+	}
+	b := map[string]interface{}{
+		"image":        map[string]interface{}{"repository": "wordpress", "tag": "5.1.1"},
+		"replicaCount": float64(1),
+		"ingress":      map[string]interface{}{"enabled": false},
+	}
+
+	assert.Equal(t, []displayers.ChartValueChange{
+		{Key: "image.tag", Change: displayers.ValueChanged, From: "5.1.0", To: "5.1.1"},
+		{Key: "ingress", Change: displayers.ValueAdded, To: `{"enabled":false}`},
+		{Key: "persistence", Change: displayers.ValueRemoved, From: `{"enabled":true}`},
+	}, diffValues("", a, b))
+
+	assert.Empty(t, diffValues("", a, a))
+}
+
+func TestRunChartDiffConstraint(t *testing.T) {
+	appMgr := &fakeAppMgr{values: map[string]string{
+		"stable/wordpress@5.6.0": "replicas: 1\n",
+		"stable/wordpress@5.7.1": "replicas: 2\n",
+		"stable/wordpress@5.7.2": "replicas: 3\n",
+		"stable/wordpress@6.0.0": "replicas: 4\n",
+	}}
+
+	var out bytes.Buffer
+	config := fakeHubConfig(&out, appMgr, nil, "wordpress")
+	config.Ankr = testConfig{types.ArgFromSlug: "stable@5.6.0", types.ArgToSlug: "stable@~5.7"}
+
+	assert.NoError(t, RunChartDiff(config))
+	assert.Contains(t, out.String(), "5.7.2")
+	assert.Contains(t, out.String(), "App version: v5.6.0 → v5.7.2")
+}
+
+func TestRunChartDiffMissingVersion(t *testing.T) {
+	// the hub returns the values of 5.7.1 but doesn't list the version
+	appMgr := &versionlessAppMgr{fakeAppMgr{values: map[string]string{
+		"stable/wordpress@5.6.0": "replicas: 1\n",
+		"stable/wordpress@5.7.1": "replicas: 2\n",
+	}}}
+
+	var out bytes.Buffer
+	config := fakeHubConfig(&out, nil, nil, "wordpress")
+	config.AppMgr = func() (gwtaskmgr.AppMgrClient, error) { return appMgr, nil }
+	config.Ankr = testConfig{types.ArgFromSlug: "stable@5.6.0", types.ArgToSlug: "stable@5.7.1"}
+
+	assert.EqualError(t, RunChartDiff(config), "chart wordpress has no version 5.7.1 in stable")
+}
+
+// versionlessAppMgr leaves 5.7.1 out of the versions of every chart.
+type versionlessAppMgr struct {
+	fakeAppMgr
+}
+
+func (f *versionlessAppMgr) ChartDetail(ctx context.Context, in *gwtaskmgr.ChartDetailRequest, opts ...grpc.CallOption) (*gwtaskmgr.ChartDetailResponse, error) {
+	r, err := f.fakeAppMgr.ChartDetail(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []*gwtaskmgr.ChartVersionDetail{}
+	for _, v := range r.ChartVersionDetails {
+		if v.ChartVer != "5.7.1" {
+			versions = append(versions, v)
+		}
+	}
+	r.ChartVersionDetails = versions
+	return r, nil
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/Masterminds/sprig"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/ignore"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// lintMessage is a problem found in a chart.
+type lintMessage struct {
+	Error bool
+	Path  string
+	Msg   string
+}
+
+func (m lintMessage) String() string {
+	return fmt.Sprintf("%s: %s", m.Path, m.Msg)
+}
+
+// lintChart checks the metadata, values and templates of ch and of the
+// charts it depends on. Templates are only parsed, the functions they call
+// are checked by the hub when it renders them.
+func lintChart(ch *chart.Chart) []lintMessage {
+	return lintChartAt(ch, "")
+}
+
+func lintChartAt(ch *chart.Chart, prefix string) []lintMessage {
+	var msgs []lintMessage
+	add := func(isErr bool, file, format string, args ...interface{}) {
+		msgs = append(msgs, lintMessage{Error: isErr, Path: prefix + file, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	md := ch.Metadata
+	switch {
+	case md == nil:
+		add(true, "Chart.yaml", "file is missing")
+	default:
+		if md.ApiVersion == "" {
+			add(true, "Chart.yaml", "apiVersion is required")
+		}
+		if md.Name == "" {
+			add(true, "Chart.yaml", "name is required")
+		}
+		if md.Version == "" {
+			add(true, "Chart.yaml", "version is required")
+		} else if _, err := semver.NewVersion(md.Version); err != nil {
+			add(true, "Chart.yaml", "version %q is not a valid semantic version", md.Version)
+		}
+	}
+
+	if ch.Values != nil {
+		if _, err := chartutil.ReadValues([]byte(ch.Values.Raw)); err != nil {
+			add(true, "values.yaml", "%v", err)
+		}
+	}
+
+	if len(ch.Templates) == 0 {
+		add(false, "templates/", "chart has no templates")
+	}
+	for _, t := range ch.Templates {
+		if err := parseTemplate(t.Name, string(t.Data)); err != nil {
+			add(true, t.Name, "%v", err)
+		}
+	}
+
+	for _, dep := range ch.Dependencies {
+		name := "?"
+		if dep.Metadata != nil {
+			name = dep.Metadata.Name
+		}
+		msgs = append(msgs, lintChartAt(dep, prefix+"charts/"+name+"/")...)
+	}
+
+	return msgs
+}
+
+// parseTemplate checks the syntax of a template and that the functions it
+// calls are known to helm.
+func parseTemplate(name, text string) error {
+	_, err := template.New(name).Funcs(templateFuncs()).Parse(text)
+	return err
+}
+
+// templateFuncs returns the functions helm makes available to templates.
+// Parsing only needs their names, so the ones helm adds to sprig's are
+// stubbed.
+func templateFuncs() template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+	noop := func(...interface{}) string { return "" }
+	for _, name := range []string{"include", "required", "tpl", "toToml", "toYaml", "fromYaml", "toJson", "fromJson"} {
+		funcs[name] = noop
+	}
+
+	return funcs
+}
+
+// checkLint reports the warnings of msgs and fails if any is an error.
+func checkLint(name string, msgs []lintMessage) error {
+	var errs []string
+	for _, m := range msgs {
+		if m.Error {
+			errs = append(errs, m.String())
+		} else {
+			warn(m.String())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("chart %s failed lint:\n  %s", name, strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// packageChartDir packages the chart in dir as a gzipped tar archive, the
+// way 'helm package' does, leaving out the files matched by .helmignore.
+func packageChartDir(dir, name string) ([]byte, error) {
+	top, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := ignore.Empty()
+	if _, err := os.Stat(filepath.Join(top, ignore.HelmIgnore)); err == nil {
+		if rules, err = ignore.ParseFile(filepath.Join(top, ignore.HelmIgnore)); err != nil {
+			return nil, err
+		}
+	}
+	rules.AddDefaults()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+
+	err = filepath.Walk(top, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(top, file)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if rules.Ignore(rel, fi) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		hdr := &tar.Header{
+			Name:    path.Join(name, rel),
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// loadUploadChart reads the chart to upload from file, a chart archive or
+// a chart directory, checks it and returns it packaged.
+func loadUploadChart(file, name, version string) ([]byte, error) {
+	fi, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var ch *chart.Chart
+	var data []byte
+	if fi.IsDir() {
+		if ch, err = chartutil.LoadDir(file); err != nil {
+			return nil, fmt.Errorf("unable to load chart from %s: %v", file, err)
+		}
+	} else {
+		if data, err = ioutil.ReadFile(file); err != nil {
+			return nil, err
+		}
+		if ch, err = chartutil.LoadArchive(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("%s is not a chart archive: %v", file, err)
+		}
+	}
+
+	if err := checkLint(name, lintChart(ch)); err != nil {
+		return nil, err
+	}
+
+	if ch.Metadata.Version != version {
+		return nil, fmt.Errorf("Chart.yaml version %s does not match the upload version %s", ch.Metadata.Version, version)
+	}
+	if ch.Metadata.Name != name {
+		warn(fmt.Sprintf("Chart.yaml names the chart %s, it is uploaded as %s", ch.Metadata.Name, name))
+	}
+
+	if fi.IsDir() {
+		return packageChartDir(file, ch.Metadata.Name)
+	}
+	return data, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestLintChart(t *testing.T) {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{ApiVersion: "v1", Name: "web", Version: "1.0.0"},
+		Templates: []*chart.Template{
+			{Name: "templates/ok.yaml", Data: []byte(`name: {{ .Values.name | quote }}`)},
+			{Name: "templates/bad.yaml", Data: []byte(`name: {{ .Values.name `)},
+		},
+	}
+
+	msgs := lintChart(ch)
+	assert.Len(t, msgs, 1)
+	assert.True(t, msgs[0].Error)
+	assert.Equal(t, "templates/bad.yaml", msgs[0].Path)
+
+	ch = &chart.Chart{Metadata: &chart.Metadata{Name: "web"}}
+	msgs = lintChart(ch)
+	assert.Len(t, msgs, 3, "missing apiVersion, version and templates: %v", msgs)
+}
+
+func TestParseTemplate(t *testing.T) {
+	assert.NoError(t, parseTemplate("templates/cm.yaml", `data: {{ include "web.labels" . | nindent 2 }}`))
+	assert.NoError(t, parseTemplate("templates/cm.yaml", `{{/* comment */}}name: {{ required "name" .Values.name }}`))
+	assert.Error(t, parseTemplate("templates/cm.yaml", `name: {{ nosuchfunc .Values.name }}`))
+}
+
+func TestPackageChartDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chart")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "templates"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("name: web\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "templates", "svc.yaml"), []byte("kind: Service\n"), 0644))
+
+	data, err := packageChartDir(dir, "web")
+	assert.NoError(t, err)
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	tr := tar.NewReader(zr)
+
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		names = append(names, hdr.Name)
+	}
+	assert.Equal(t, []string{"web/Chart.yaml", "web/templates/svc.yaml"}, names)
+}
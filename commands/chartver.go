@@ -0,0 +1,140 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
+	"github.com/Masterminds/semver"
+	"google.golang.org/grpc/status"
+)
+
+// latestVersion selects the highest released version of a chart.
+const latestVersion = "latest"
+
+// rangeSpaceRe matches the spaces after the operator of a term, or between
+// the terms of a range such as >=1.0 <2.0.
+var rangeSpaceRe = regexp.MustCompile(`([<>=!~^])\s+|\s*,\s*|\s+`)
+
+// exactVersionRe matches a version naming a single release, as opposed to
+// a partial version such as 1.4 which is resolved like a constraint.
+var exactVersionRe = regexp.MustCompile(`^v?[0-9]+\.[0-9]+\.[0-9]+([-+].*)?$`)
+
+// versionConstraint returns the constraint version asks for, or nil if
+// version names an exact version and needs no resolving. Versions that are
+// neither semver nor a constraint are taken as exact.
+func versionConstraint(version string) *semver.Constraints {
+	if version == "" || exactVersionRe.MatchString(version) {
+		return nil
+	}
+	if version == latestVersion {
+		version = "*"
+	}
+
+	constraint, err := semver.NewConstraint(normalizeConstraint(version))
+	if err != nil {
+		return nil
+	}
+
+	return constraint
+}
+
+// normalizeConstraint separates the terms of the ranges of constraint with
+// commas, which the semver package needs. Hyphen ranges such as 1.0 - 1.4
+// are left as they are.
+func normalizeConstraint(constraint string) string {
+	ranges := strings.Split(constraint, "||")
+	for i, r := range ranges {
+		r = strings.TrimSpace(r)
+		if !strings.Contains(r, " - ") {
+			r = rangeSpaceRe.ReplaceAllStringFunc(r, func(sep string) string {
+				if op := strings.TrimSpace(sep); op != "" && op != "," {
+					return op
+				}
+				return ", "
+			})
+		}
+		ranges[i] = r
+	}
+
+	return strings.Join(ranges, " || ")
+}
+
+// selectVersion returns the highest of versions satisfying the constraint
+// version, and false if there is none. Only versions that are semver are
+// considered, and prereleases only if the constraint names one.
+func selectVersion(version string, versions []string) (string, bool) {
+	constraint := versionConstraint(version)
+	if constraint == nil {
+		return "", false
+	}
+	prerelease := strings.Contains(version, "-") && !strings.Contains(version, " - ")
+
+	candidates := semver.Collection{}
+	for _, v := range versions {
+		sv, err := semver.NewVersion(v)
+		if err != nil || !constraint.Check(sv) || (sv.Prerelease() != "" && !prerelease) {
+			continue
+		}
+		candidates = append(candidates, sv)
+	}
+
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	sort.Sort(candidates)
+	return candidates[len(candidates)-1].Original(), true
+}
+
+// resolveChartVersion returns the version of chart name in repo that
+// version selects. A constraint such as ~1.4, ^2, >=1.0 <2.0 or latest is
+// resolved against the versions the hub has and the selected version is
+// printed, any other version is returned as it is.
+func resolveChartVersion(c *CmdConfig, name, repo, version string) (string, error) {
+	if versionConstraint(version) == nil {
+		return version, nil
+	}
+
+	appClient, err := c.AppMgr()
+	if err != nil {
+		return "", err
+	}
+
+	r, err := appClient.ChartDetail(context.Background(), &gwtaskmgr.ChartDetailRequest{
+		ChartName: name,
+		ChartRepo: repo,
+	})
+	if err != nil {
+		return "", fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+	}
+
+	versions := []string{}
+	for _, v := range r.ChartVersionDetails {
+		versions = append(versions, v.ChartVer)
+	}
+
+	selected, ok := selectVersion(version, versions)
+	if !ok {
+		return "", fmt.Errorf("no version of chart %s in %s matches %s", name, repo, version)
+	}
+
+	notice(fmt.Sprintf("Using chart %s version %s", name, selected))
+	return selected, nil
+}
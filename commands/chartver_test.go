@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectVersion(t *testing.T) {
+	versions := []string{"1.3.0", "1.4.0", "1.4.2", "1.5.0", "2.0.0", "2.1.0", "2.2.0-beta.1", "nightly"}
+
+	for _, tc := range []struct {
+		version, want string
+	}{
+		{"~1.4", "1.4.2"},
+		{"^1.4", "1.5.0"},
+		{"^2", "2.1.0"},
+		{">=1.0 <2.0", "1.5.0"},
+		{">= 1.0, < 2.0", "1.5.0"},
+		{"<1.4 || >=2.0", "2.1.0"},
+		{"1.4.x", "1.4.2"},
+		{"1.3 - 1.4", "1.4.0"},
+		{">=2.0", "2.1.0"},
+		{">=2.2.0-0", "2.2.0-beta.1"},
+		{"latest", "2.1.0"},
+	} {
+		got, ok := selectVersion(tc.version, versions)
+		assert.True(t, ok, tc.version)
+		assert.Equal(t, tc.want, got, tc.version)
+	}
+
+	_, ok := selectVersion("^3", versions)
+	assert.False(t, ok)
+
+	for _, exact := range []string{"", "1.4.2", "2.2.0-beta.1", "nightly"} {
+		assert.Nil(t, versionConstraint(exact), exact)
+	}
+}
@@ -14,24 +14,17 @@ limitations under the License.
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
-	"log"
-	"time"
-
-	"github.com/spf13/viper"
+	"strings"
 
 	"github.com/Ankr-network/ankrctl/commands/displayers"
-	"github.com/gobwas/glob"
+	"github.com/Ankr-network/ankrctl/types"
 	"github.com/spf13/cobra"
 
 	"context"
 
-	ankr_const "github.com/Ankr-network/dccn-common"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
-	gwdcmgr "github.com/Ankr-network/dccn-common/protos/gateway/dcmgr/v1"
-	gwusermgr "github.com/Ankr-network/dccn-common/protos/gateway/usermgr/v1"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -52,114 +45,160 @@ func clusterCmd() *Command {
 	//DCCN-CLI cluster list
 	cmdRunClusterList := CmdBuilder(cmd, RunClusterList, "list [GLOB]", "list cluster", Writer,
 		aliasOpt("ls"), displayerType(&displayers.Cluster{}), docCategories("cluster"))
-	_ = cmdRunClusterList
+	filterFlags(cmdRunClusterList)
+	watchFlags(cmdRunClusterList)
 
 	//DCCN-CLI cluster network info
 	cmdRunNetworkInfo := CmdBuilder(cmd, RunNetworkInfo, "network", "list network info", Writer,
-		aliasOpt("ni"), docCategories("cluster"))
+		aliasOpt("ni"), displayerType(&displayers.NetworkInfo{}), docCategories("cluster"))
 	_ = cmdRunNetworkInfo
 
+	//DCCN-CLI cluster top
+	cmdRunClusterTop := CmdBuilder(cmd, RunClusterTop, "top [GLOB]", "show used and free capacity of clusters", Writer,
+		displayerType(&displayers.ClusterUsage{}), docCategories("cluster"))
+	filterFlags(cmdRunClusterTop)
+	watchFlags(cmdRunClusterTop)
+
+	//DCCN-CLI cluster describe
+	cmdRunClusterDescribe := CmdBuilder(cmd, RunClusterDescribe, "describe <cluster-id>",
+		"show the capacity of a cluster", Writer, aliasOpt("ds"),
+		displayerType(&displayers.ClusterResources{}), docCategories("cluster"))
+	_ = cmdRunClusterDescribe
+
+	//DCCN-CLI cluster recommend
+	cmdRunClusterRecommend := CmdBuilder(cmd, RunClusterRecommend, "recommend",
+		"rank clusters by free capacity for a namespace", Writer, aliasOpt("rc"),
+		displayerType(&displayers.ClusterRecommendation{}), docCategories("cluster"))
+	AddStringFlag(cmdRunClusterRecommend, types.ArgNsCpuLimitSlug, "", "", "CPU the namespace needs (in vCPUs, or mCPUs such as 500m)")
+	AddStringFlag(cmdRunClusterRecommend, types.ArgNsMemLimitSlug, "", "", "Memory the namespace needs (in GiB, or with a unit such as 512Mi)")
+	AddStringFlag(cmdRunClusterRecommend, types.ArgNsStorageLimitSlug, "", "", "Storage the namespace needs (in GiB, or with a unit such as 20G)")
+	AddStringFlag(cmdRunClusterRecommend, types.ArgNearSlug, "", "", "Prefer clusters near this location, as lat,lng")
+	AddStringFlag(cmdRunClusterRecommend, types.ArgNameContainsSlug, "", "", "Only clusters whose name contains this text")
+
 	return cmd
 }
 
 // RunClusterList returns a list of cluster.
 func RunClusterList(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
+	dcMgr, err := c.DCAPI()
+	if err != nil {
+		return err
 	}
 
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	matches := []glob.Glob{}
-	for _, globStr := range c.Args {
-		g, err := glob.Compile(globStr)
+	return c.DisplayList(func() (displayers.Displayable, error) {
+		r, err := dcMgr.DataCenterList(context.Background(), &common_proto.Empty{})
 		if err != nil {
-			return fmt.Errorf("unknown glob %q", globStr)
+			return nil, fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 		}
 
-		matches = append(matches, g)
-	}
+		var clusters []common_proto.DataCenterStatus
+		for _, cluster := range r.DcList {
+			if cluster.GeoLocation == nil {
+				cluster.GeoLocation = &common_proto.GeoLocation{}
+			}
+			clusters = append(clusters, *cluster)
+		}
 
-	var matchedList []common_proto.DataCenterStatus
+		return &displayers.Cluster{Clusters: clusters}, nil
+	})
+}
 
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+// RunNetworkInfo returns a overview of apps.
+func RunNetworkInfo(c *CmdConfig) error {
+
+	dcMgr, err := c.DCAPI()
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-	defer conn.Close()
-	dcMgr := gwdcmgr.NewDCAPIClient(conn)
-
-	r, err := dcMgr.DataCenterList(tokenctx, &common_proto.Empty{})
+	resp, err := dcMgr.NetworkInfo(context.Background(), &common_proto.Empty{})
 	if err != nil {
 		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 	}
+	item := &displayers.NetworkInfo{Network: displayers.NetworkStats{
+		UserCount:      int64(resp.UserCount),
+		HostCount:      int64(resp.HostCount),
+		NamespaceCount: int64(resp.NsCount),
+		ContainerCount: int64(resp.ContainerCount),
+		Traffic:        int64(resp.Traffic),
+	}}
 
-	for _, cluster := range r.DcList {
-		var skip = true
-		if len(matches) == 0 {
-			skip = false
-		} else {
-			for _, m := range matches {
-				if m.Match(cluster.DcName) {
-					skip = false
-				}
-			}
-		}
-
-		if !skip {
-			if cluster.GeoLocation == nil {
-				cluster.GeoLocation = &common_proto.GeoLocation{}
-			}
-			matchedList = append(matchedList, *cluster)
-		}
-	}
-	item := &displayers.Cluster{Clusters: matchedList}
 	return c.Display(item)
 }
 
-// RunNetworkInfo returns a overview of apps.
-func RunNetworkInfo(c *CmdConfig) error {
+// RunClusterTop shows the used and free capacity of clusters.
+func RunClusterTop(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
+	dcMgr, err := c.DCAPI()
+	if err != nil {
+		return err
 	}
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
+
+	return c.DisplayList(func() (displayers.Displayable, error) {
+		r, err := dcMgr.DataCenterList(context.Background(), &common_proto.Empty{})
+		if err != nil {
+			return nil, fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+		}
+
+		item := &displayers.ClusterUsage{Color: colorTable(c.Out)}
+		for _, dc := range r.DcList {
+			item.Usages = append(item.Usages, clusterUsage(dc))
+		}
+
+		return item, nil
 	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
+}
 
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+// RunClusterDescribe shows the capacity of a cluster.
+func RunClusterDescribe(c *CmdConfig) error {
+
+	if len(c.Args) < 1 {
+		return types.NewMissingArgsErr(c.NS)
 	}
-	defer conn.Close()
 
-	dcMgr := gwdcmgr.NewDCAPIClient(conn)
+	dcMgr, err := c.DCAPI()
 	if err != nil {
 		return err
 	}
-	resp, err := dcMgr.NetworkInfo(tokenctx, &common_proto.Empty{})
+
+	r, err := dcMgr.DataCenterList(context.Background(), &common_proto.Empty{})
 	if err != nil {
 		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 	}
-	fmt.Printf("User Count:\t\t%v\nHost Count:\t\t%v\nNamespace Count:\t%v\nContainer Count:\t%v\nTraffic:\t%v\n",
-		resp.UserCount, resp.HostCount, resp.NsCount, resp.ContainerCount, resp.Traffic)
 
-	return nil
+	for _, dc := range r.DcList {
+		if dc.DcId == c.Args[0] || dc.DcName == c.Args[0] {
+			return c.Display(&displayers.ClusterResources{Usage: clusterUsage(dc), Color: colorTable(c.Out)})
+		}
+	}
+
+	return fmt.Errorf("cluster %s not found", c.Args[0])
+}
+
+// clusterUsage decodes the capacity of a cluster from its heartbeat metrics,
+// which report CPU in mCPUs and memory and storage in MiB.
+func clusterUsage(dc *common_proto.DataCenterStatus) displayers.ClusterUsageInfo {
+	u := displayers.ClusterUsageInfo{
+		ID:     dc.DcId,
+		Name:   dc.DcName,
+		Status: strings.ToLower(dc.DcStatus.String()),
+	}
+	if dc.GeoLocation != nil {
+		u.Lat, u.Lng = dc.GeoLocation.Lat, dc.GeoLocation.Lng
+	}
+	if dc.DcAttributes != nil {
+		u.WalletAddress = dc.DcAttributes.WalletAddress
+	}
+
+	metrics := displayers.Metrics{}
+	if dc.DcHeartbeatReport != nil {
+		_ = json.Unmarshal([]byte(dc.DcHeartbeatReport.Metrics), &metrics)
+	}
+
+	u.CPU = displayers.NewResourceUsage(float64(metrics.UsedCPU)/types.MilliCPUPerCPU, float64(metrics.TotalCPU)/types.MilliCPUPerCPU)
+	u.Memory = displayers.NewResourceUsage(float64(metrics.UsedMemory)/types.MiBPerGiB, float64(metrics.TotalMemory)/types.MiBPerGiB)
+	u.Storage = displayers.NewResourceUsage(float64(metrics.UsedStorage)/types.MiBPerGiB, float64(metrics.TotalStorage)/types.MiBPerGiB)
+	u.Endpoints, u.Images, u.NetworkIO = metrics.EndPointCount, metrics.ImageCount, metrics.NetworkIO
+
+	return u
 }
@@ -23,7 +23,8 @@ type Command struct {
 	// DocCategories are the documentation categories this command belongs to.
 	DocCategories []string
 
-	fmtCols []string
+	fmtCols  []string
+	sortable bool
 
 	childCommands []*Command
 	IsIndex       bool
@@ -33,6 +33,10 @@ func aliasOpt(aliases ...string) cmdOption {
 func displayerType(d displayers.Displayable) cmdOption {
 	return func(c *Command) {
 		c.fmtCols = d.Cols()
+		if w, ok := d.(displayers.Wide); ok {
+			c.fmtCols = w.WideCols()
+		}
+		_, c.sortable = d.(displayers.Sortable)
 	}
 }
 
@@ -0,0 +1,386 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Ankr-network/ankrctl/types"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
+	"github.com/fatih/color"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+var (
+	diffHeader  = color.New(color.Bold).SprintFunc()
+	diffHunk    = color.New(color.FgCyan).SprintFunc()
+	diffRemoved = color.New(color.FgRed).SprintFunc()
+	diffAdded   = color.New(color.FgGreen).SprintFunc()
+)
+
+// appState is the part of an app compared by 'app diff'.
+type appState struct {
+	Name         string
+	ChartRepo    string
+	ChartName    string
+	ChartVer     string
+	Namespace    string
+	CpuLimit     uint32
+	MemLimit     uint32
+	StorageLimit uint32
+	Values       map[string]interface{}
+}
+
+func (s appState) lines() []string {
+	lines := []string{
+		fmt.Sprintf("app: %s", s.Name),
+		"chart:",
+		fmt.Sprintf("  repo: %s", s.ChartRepo),
+		fmt.Sprintf("  name: %s", s.ChartName),
+		fmt.Sprintf("  version: %s", s.ChartVer),
+		fmt.Sprintf("namespace: %s", s.Namespace),
+		fmt.Sprintf("  cpu-limit: %d mCPUs", s.CpuLimit),
+		fmt.Sprintf("  mem-limit: %d MiB", s.MemLimit),
+		fmt.Sprintf("  storage-limit: %d MiB", s.StorageLimit),
+	}
+
+	if len(s.Values) == 0 {
+		return append(lines, "values: {}")
+	}
+
+	lines = append(lines, "values:")
+	b, err := yaml.Marshal(s.Values)
+	if err != nil {
+		return append(lines, fmt.Sprintf("  %v", s.Values))
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(b), "\n"), "\n") {
+		lines = append(lines, "  "+line)
+	}
+
+	return lines
+}
+
+// liveAppState returns the state of a live app, with the limits of its
+// namespace taken from the namespace list.
+func liveAppState(r *common_proto.AppReport, namespaces []*common_proto.NamespaceReport) appState {
+	d := r.AppDeployment
+	s := appState{
+		Name:      d.AppName,
+		ChartRepo: d.ChartDetail.ChartRepo,
+		ChartName: d.ChartDetail.ChartName,
+		ChartVer:  d.ChartDetail.ChartVer,
+		Namespace: fmt.Sprintf("%s (%s)", d.Namespace.NsName, d.Namespace.NsId),
+	}
+
+	if ns, _ := findNamespace(namespaces, NamespaceRef{ID: d.Namespace.NsId}); ns != nil {
+		s.CpuLimit = ns.Namespace.NsCpuLimit
+		s.MemLimit = ns.Namespace.NsMemLimit
+		s.StorageLimit = ns.Namespace.NsStorageLimit
+	}
+
+	return s
+}
+
+// desiredAppState returns the state a manifest asks for. Namespace limits
+// the manifest leaves out keep their live value.
+func desiredAppState(app AppManifest, namespaces []*common_proto.NamespaceReport) (appState, error) {
+	target, err := app.target()
+	if err != nil {
+		return appState{Name: app.Name}, err
+	}
+
+	s := appState{
+		Name:      app.Name,
+		ChartRepo: target.Repo,
+		ChartName: target.Name,
+		ChartVer:  target.Version,
+	}
+
+	ns, err := findNamespace(namespaces, app.Namespace)
+	if err != nil {
+		return s, err
+	}
+
+	if ns == nil {
+		if app.Namespace.ID != "" {
+			return s, fmt.Errorf("namespace %s not found", app.Namespace.ID)
+		}
+
+		req, err := newNamespaceRequest(app.Namespace)
+		if err != nil {
+			return s, err
+		}
+
+		s.Namespace = fmt.Sprintf("%s (new)", app.Namespace.Name)
+		s.CpuLimit, s.MemLimit, s.StorageLimit = req.NsCpuLimit, req.NsMemLimit, req.NsStorageLimit
+		return s, nil
+	}
+
+	s.Namespace = fmt.Sprintf("%s (%s)", ns.Namespace.NsName, ns.Namespace.NsId)
+	s.CpuLimit = ns.Namespace.NsCpuLimit
+	s.MemLimit = ns.Namespace.NsMemLimit
+	s.StorageLimit = ns.Namespace.NsStorageLimit
+
+	req, _, err := namespaceUpdateRequest(ns.Namespace, app.Namespace)
+	if err != nil {
+		return s, err
+	}
+	if req != nil {
+		s.CpuLimit, s.MemLimit, s.StorageLimit = req.NsCpuLimit, req.NsMemLimit, req.NsStorageLimit
+	}
+
+	return s, nil
+}
+
+// RunAppDiff shows how the live apps differ from a manifest.
+func RunAppDiff(c *CmdConfig) error {
+
+	file, err := c.Ankr.GetString(c.NS, types.ArgFileSlug)
+	if err != nil {
+		return err
+	}
+
+	m, err := readManifest(file)
+	if err != nil {
+		return err
+	}
+
+	appClient, err := c.AppMgr()
+	if err != nil {
+		return err
+	}
+
+	apps, err := appClient.AppList(context.Background(), &common_proto.Empty{})
+	if err != nil {
+		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+	}
+
+	namespaces, err := appClient.NamespaceList(context.Background(), &common_proto.Empty{})
+	if err != nil {
+		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+	}
+
+	// the values of each chart are fetched once
+	fetched := map[ChartRef]map[string]interface{}{}
+	values := func(ref ChartRef) (map[string]interface{}, error) {
+		if v, ok := fetched[ref]; ok {
+			return v, nil
+		}
+
+		v, err := chartValues(context.Background(), appClient, ref)
+		if err != nil {
+			return nil, err
+		}
+		fetched[ref] = v
+		return v, nil
+	}
+
+	drift, err := writeAppDiff(c.Out, m, apps, namespaces, values)
+	if err != nil {
+		return err
+	}
+
+	if drift {
+		return &exitError{code: exitDrift}
+	}
+
+	return nil
+}
+
+// writeAppDiff writes a unified diff from the live state to the manifest for
+// every app of the manifest, and reports whether any differ. values returns
+// the values.yaml of a chart.
+func writeAppDiff(out io.Writer, m *Manifest, apps *gwtaskmgr.AppListResponse,
+	namespaces *gwtaskmgr.NamespaceListResponse, values func(ChartRef) (map[string]interface{}, error)) (bool, error) {
+
+	drift := false
+	for _, app := range m.Apps {
+		desired, err := desiredAppState(app, namespaces.NsReports)
+		if err != nil {
+			return drift, fmt.Errorf("app %s: %v", app.Name, err)
+		}
+
+		// an app runs with its values merged over the values of its chart
+		chartValues, err := values(app.Chart)
+		if err != nil {
+			return drift, fmt.Errorf("app %s: %v", app.Name, err)
+		}
+		desiredValues := mergeValues(mergeValues(map[string]interface{}{}, chartValues), app.Values)
+		if desired.Values, err = normalizeValues(desiredValues); err != nil {
+			return drift, fmt.Errorf("app %s: %v", app.Name, err)
+		}
+
+		live, err := findApp(apps.AppReports, app.Name)
+		if err != nil {
+			return drift, err
+		}
+
+		from, liveLines := "/dev/null", []string{}
+		if live != nil {
+			from = "live/" + app.Name
+			state := liveAppState(live, namespaces.NsReports)
+
+			chart := live.AppDeployment.ChartDetail
+			state.Values, err = values(ChartRef{Name: chart.ChartName, Repo: chart.ChartRepo, Version: chart.ChartVer})
+			if err != nil {
+				return drift, fmt.Errorf("app %s: %v", app.Name, err)
+			}
+			liveLines = state.lines()
+		}
+
+		hunks := unifiedDiff(liveLines, desired.lines(), diffContext)
+		if len(hunks) == 0 {
+			continue
+		}
+		drift = true
+
+		fmt.Fprintln(out, diffHeader("--- "+from))
+		fmt.Fprintln(out, diffHeader("+++ manifest/"+app.Name))
+		for _, line := range hunks {
+			switch line[0] {
+			case '@':
+				line = diffHunk(line)
+			case '-':
+				line = diffRemoved(line)
+			case '+':
+				line = diffAdded(line)
+			}
+			fmt.Fprintln(out, line)
+		}
+	}
+
+	return drift, nil
+}
+
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// diffLines returns the edits turning a into b, based on their longest
+// common subsequence.
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := []diffOp{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+
+	return ops
+}
+
+// unifiedDiff returns the hunks of a unified diff from a to b, with n lines
+// of context around each change. It returns nothing if a and b are equal.
+func unifiedDiff(a, b []string, n int) []string {
+	ops := diffLines(a, b)
+
+	out := []string{}
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// a hunk runs until the next change is too far away to share context
+		start, end := i-n, i
+		if start < 0 {
+			start = 0
+		}
+		for j := i; j < len(ops) && j-end <= 2*n; j++ {
+			if ops[j].kind != ' ' {
+				end = j
+			}
+		}
+		stop := end + n + 1
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		aLine, bLine := 1, 1
+		for _, op := range ops[:start] {
+			if op.kind != '+' {
+				aLine++
+			}
+			if op.kind != '-' {
+				bLine++
+			}
+		}
+
+		aLen, bLen := 0, 0
+		lines := []string{}
+		for _, op := range ops[start:stop] {
+			if op.kind != '+' {
+				aLen++
+			}
+			if op.kind != '-' {
+				bLen++
+			}
+			lines = append(lines, string(op.kind)+op.line)
+		}
+		if aLen == 0 {
+			aLine--
+		}
+		if bLen == 0 {
+			bLine--
+		}
+
+		out = append(out, fmt.Sprintf("@@ -%d,%d +%d,%d @@", aLine, aLen, bLine, bLen))
+		out = append(out, lines...)
+		i = stop
+	}
+
+	return out
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	a := []string{"app: web", "chart:", "  repo: stable", "  name: wordpress", "  version: 5.6.0", "namespace: a"}
+	b := []string{"app: web", "chart:", "  repo: stable", "  name: wordpress", "  version: 5.7.0", "namespace: a"}
+
+	assert.Empty(t, unifiedDiff(a, a, 3))
+
+	assert.Equal(t, []string{
+		"@@ -3,4 +3,4 @@",
+		"   repo: stable",
+		"   name: wordpress",
+		"-  version: 5.6.0",
+		"+  version: 5.7.0",
+		" namespace: a",
+	}, unifiedDiff(a, b, 2))
+
+	assert.Equal(t, []string{
+		"@@ -0,0 +1,2 @@",
+		"+app: web",
+		"+chart:",
+	}, unifiedDiff(nil, b[:2], 3))
+
+	long := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}
+	changed := []string{"x", "2", "3", "4", "5", "6", "7", "8", "y"}
+	assert.Equal(t, []string{
+		"@@ -1,2 +1,2 @@",
+		"-1",
+		"+x",
+		" 2",
+		"@@ -8,2 +8,2 @@",
+		" 8",
+		"-9",
+		"+y",
+	}, unifiedDiff(long, changed, 1))
+}
+
+func TestWriteAppDiffValues(t *testing.T) {
+	ns := &common_proto.Namespace{NsId: "ns-1", NsName: "team-a"}
+	namespaces := &gwtaskmgr.NamespaceListResponse{NsReports: []*common_proto.NamespaceReport{{Namespace: ns}}}
+
+	app := AppManifest{Name: "web", Chart: ChartRef{Name: "wordpress", Repo: "stable", Version: "5.7.0"},
+		Namespace: NamespaceRef{ID: "ns-1"}, Values: map[string]interface{}{"replicas": 3}}
+	target, err := app.target()
+	assert.NoError(t, err)
+
+	apps := &gwtaskmgr.AppListResponse{AppReports: []*common_proto.AppReport{
+		{AppDeployment: &common_proto.AppDeployment{AppId: "app-1", AppName: "web", Namespace: ns,
+			ChartDetail: &common_proto.ChartDetail{ChartName: "web", ChartRepo: userChartRepo, ChartVer: target.Version}}},
+	}}
+	chart := map[string]interface{}{"replicas": float64(1), "service": "ClusterIP"}
+	saved := map[string]interface{}{"replicas": float64(2), "service": "ClusterIP"}
+	values := func(ref ChartRef) (map[string]interface{}, error) {
+		if ref == app.Chart {
+			return chart, nil
+		}
+		return saved, nil
+	}
+
+	var out bytes.Buffer
+	drift, err := writeAppDiff(&out, &Manifest{Apps: []AppManifest{app}}, apps, namespaces, values)
+	assert.NoError(t, err)
+	assert.True(t, drift)
+	assert.Contains(t, out.String(), "-  replicas: 2\n+  replicas: 3\n")
+
+	saved["replicas"] = float64(3)
+	out.Reset()
+	drift, err = writeAppDiff(&out, &Manifest{Apps: []AppManifest{app}}, apps, namespaces, values)
+	assert.NoError(t, err)
+	assert.False(t, drift, out.String())
+	assert.Equal(t, float64(1), chart["replicas"], "the chart values are left alone")
+
+	// the chart defaults count as desired values
+	delete(saved, "service")
+	out.Reset()
+	drift, err = writeAppDiff(&out, &Manifest{Apps: []AppManifest{app}}, apps, namespaces, values)
+	assert.NoError(t, err)
+	assert.True(t, drift)
+	assert.Contains(t, out.String(), "+  service: ClusterIP\n")
+}
@@ -25,7 +25,9 @@ type AppReport struct {
 	Apps []*pb.AppReport
 }
 
-var _ Displayable = &AppReport{}
+var _ Filterable = &AppReport{}
+var _ Sortable = &AppReport{}
+var _ Wide = &AppReport{}
 
 func (d *AppReport) JSON(out io.Writer) error {
 	return writeJSON(d.Apps, out)
@@ -39,13 +41,38 @@ func (d *AppReport) Cols() []string {
 	return cols
 }
 
+func (d *AppReport) WideCols() []string {
+	return append(d.Cols(), "NamespaceID", "ClusterID")
+}
+
 func (d *AppReport) ColMap() map[string]string {
 	return map[string]string{
 		"ID": "ID", "Name": "Name", "ChartRepo": "Chart Repo", "ChartName": "Chart Name",
 		"ChartVersion": "Chart Version", "AppVersion": "App Version", "Namespace": "Namespace",
 		"Cluster": "Cluster", "LastModifyDate": "Last Modified Date", "CreationDate": "Creation Date",
 		"Status": "Status", "Event": "Event", "Endpoint": "Endpoint",
+		"NamespaceID": "Namespace ID", "ClusterID": "Cluster ID",
+	}
+}
+
+func (d *AppReport) Filter(keep func(i int) bool) {
+	kept := []*pb.AppReport{}
+	for i, item := range d.Apps {
+		if keep(i) {
+			kept = append(kept, item)
+		}
 	}
+
+	d.Apps = kept
+}
+
+func (d *AppReport) Reorder(order []int) {
+	sorted := make([]*pb.AppReport, len(order))
+	for i, o := range order {
+		sorted[i] = d.Apps[o]
+	}
+
+	d.Apps = sorted
 }
 
 func (d *AppReport) KV() []map[string]interface{} {
@@ -59,10 +86,100 @@ func (d *AppReport) KV() []map[string]interface{} {
 			"LastModifyDate": time.Unix(int64(d.AppDeployment.Attributes.LastModifiedDate.Seconds), 0).Format(time.RFC822),
 			"CreationDate":   time.Unix(int64(d.AppDeployment.Attributes.CreationDate.Seconds), 0).Format(time.RFC822),
 			"Status":         strings.ToLower(d.AppStatus.String()), "Event": strings.ToLower(d.AppEvent.String()),
-			"Endpoint": d.Endpoint,
+			"Endpoint": d.Endpoint, "NamespaceID": d.AppDeployment.Namespace.NsId,
+			"ClusterID": d.AppDeployment.Namespace.ClusterId,
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
+
+type AppDetail struct {
+	Apps []*pb.AppReport
+}
+
+var _ Displayable = &AppDetail{}
+
+func (d *AppDetail) JSON(out io.Writer) error {
+	return writeJSON(d.Apps, out)
+}
+
+func (d *AppDetail) Cols() []string {
+	cols := []string{
+		"ID", "Name", "Status", "Event", "Endpoint",
+	}
+	return cols
+}
+
+func (d *AppDetail) ColMap() map[string]string {
+	return map[string]string{
+		"ID": "ID", "Name": "Name", "Status": "Status", "Event": "Event",
+		"Endpoint": "Endpoint", "Detail": "Detail",
+	}
+}
+
+func (d *AppDetail) KV() []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, d := range d.Apps {
+		m := map[string]interface{}{
+			"ID": d.AppDeployment.AppId, "Name": d.AppDeployment.AppName,
+			"Status": strings.ToLower(d.AppStatus.String()), "Event": strings.ToLower(d.AppEvent.String()),
+			"Endpoint": d.Endpoint, "Detail": d.Detail,
 		}
 		out = append(out, m)
 	}
 
 	return out
 }
+
+type AppOverview struct {
+	Overview AppOverviewInfo
+}
+
+type AppOverviewInfo struct {
+	ClusterCount   int64   `json:"cluster_count"`
+	NamespaceCount int64   `json:"namespace_count"`
+	NetworkCount   int64   `json:"network_count"`
+	TotalAppCount  int64   `json:"total_app_count"`
+	CpuTotal       float64 `json:"cpu_total"`
+	CpuUsage       float64 `json:"cpu_usage"`
+	MemTotal       float64 `json:"mem_total"`
+	MemUsage       float64 `json:"mem_usage"`
+	StorageTotal   float64 `json:"storage_total"`
+	StorageUsage   float64 `json:"storage_usage"`
+}
+
+var _ Displayable = &AppOverview{}
+
+func (o *AppOverview) JSON(out io.Writer) error {
+	return writeJSON(o.Overview, out)
+}
+
+func (o *AppOverview) Cols() []string {
+	cols := []string{
+		"Clusters", "Namespaces", "Networks", "Apps", "CpuTotal", "CpuUsage",
+		"MemTotal", "MemUsage", "StorageTotal", "StorageUsage",
+	}
+	return cols
+}
+
+func (o *AppOverview) ColMap() map[string]string {
+	return map[string]string{
+		"Clusters": "Clusters", "Namespaces": "Namespaces", "Networks": "Networks", "Apps": "Apps",
+		"CpuTotal": "CPU Total", "CpuUsage": "CPU Usage", "MemTotal": "Memory Total",
+		"MemUsage": "Memory Usage", "StorageTotal": "Storage Total", "StorageUsage": "Storage Usage",
+	}
+}
+
+func (o *AppOverview) KV() []map[string]interface{} {
+	v := o.Overview
+	m := map[string]interface{}{
+		"Clusters": v.ClusterCount, "Namespaces": v.NamespaceCount, "Networks": v.NetworkCount,
+		"Apps": v.TotalAppCount, "CpuTotal": v.CpuTotal, "CpuUsage": v.CpuUsage,
+		"MemTotal": v.MemTotal, "MemUsage": v.MemUsage, "StorageTotal": v.StorageTotal,
+		"StorageUsage": v.StorageUsage,
+	}
+
+	return []map[string]interface{}{m}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	pb "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// checkColumns checks that every column of item has a header.
+func checkColumns(t *testing.T, item Displayable) {
+	t.Helper()
+	for _, col := range item.Cols() {
+		assert.NotEmpty(t, item.ColMap()[col], "%T column %q has no header", item, col)
+	}
+}
+
+func TestAppDetail(t *testing.T) {
+	item := &AppDetail{Apps: []*pb.AppReport{{
+		AppDeployment: &pb.AppDeployment{AppId: "app-1", AppName: "web"},
+		Endpoint:      "web.ankr.network",
+		Detail:        "pulling image",
+	}}}
+	checkColumns(t, item)
+
+	rows := item.KV()
+	if !assert.Len(t, rows, 1) {
+		return
+	}
+	for col, want := range map[string]string{
+		"ID": "app-1", "Name": "web", "Endpoint": "web.ankr.network", "Detail": "pulling image",
+	} {
+		assert.Equal(t, want, rows[0][col], col)
+	}
+
+	assert.Contains(t, item.ColMap(), "Detail", "the Detail column should be selectable with --format")
+}
+
+func TestAppOverview(t *testing.T) {
+	item := &AppOverview{Overview: AppOverviewInfo{
+		ClusterCount: 2, NamespaceCount: 3, NetworkCount: 1, TotalAppCount: 5,
+		CpuTotal: 8, CpuUsage: 2.5, MemTotal: 16, MemUsage: 4,
+	}}
+	checkColumns(t, item)
+
+	var out bytes.Buffer
+	assert.NoError(t, WriteTable(item, &out, "Clusters", "Apps", "CpuUsage", "MemTotal"))
+	assert.Equal(t, "Clusters    Apps    CPU Usage    Memory Total\n"+
+		"2           5       2.500000     16.000000\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, item.JSON(&out))
+	var got map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, 5.0, got["total_app_count"])
+	assert.Equal(t, 2.5, got["cpu_usage"])
+}
+
+func TestUserDetail(t *testing.T) {
+	item := &UserDetail{User: UserInfo{
+		Name: "alice", Email: "alice@ankr.network", Status: "confirmed",
+		ExtraFields: map[string]string{"Team": "infra", "Email": "other@ankr.network", "Company": "ankr"},
+	}}
+	checkColumns(t, item)
+
+	assert.Equal(t, []string{"Name", "Email", "Status", "CreationDate", "LastModifiedDate", "PubKey", "Company", "Team"},
+		item.Cols())
+
+	row := item.KV()[0]
+	assert.Equal(t, "alice@ankr.network", row["Email"], "an extra field replaced the Email column")
+	assert.Equal(t, "infra", row["Team"])
+	assert.Equal(t, "ankr", row["Company"])
+}
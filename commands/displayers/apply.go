@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"io"
+	"strings"
+)
+
+type ApplyResult struct {
+	Items []ApplyResultItem
+}
+
+type ApplyResultItem struct {
+	Name    string   `json:"name"`
+	Action  string   `json:"action"`
+	AppID   string   `json:"app_id,omitempty"`
+	Changes []string `json:"changes,omitempty"`
+	Result  string   `json:"result"`
+	Error   string   `json:"error,omitempty"`
+}
+
+var _ Displayable = &ApplyResult{}
+
+func (a *ApplyResult) JSON(out io.Writer) error {
+	return writeJSON(a.Items, out)
+}
+
+func (a *ApplyResult) Cols() []string {
+	cols := []string{
+		"Name", "Action", "ID", "Changes", "Result",
+	}
+	return cols
+}
+
+func (a *ApplyResult) ColMap() map[string]string {
+	return map[string]string{
+		"Name": "Name", "Action": "Action", "ID": "ID",
+		"Changes": "Changes", "Result": "Result", "Error": "Error",
+	}
+}
+
+func (a *ApplyResult) KV() []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, i := range a.Items {
+		result := i.Result
+		if i.Error != "" {
+			result = result + ": " + i.Error
+		}
+		m := map[string]interface{}{
+			"Name": i.Name, "Action": i.Action, "ID": i.AppID,
+			"Changes": strings.Join(i.Changes, "; "), "Result": result, "Error": i.Error,
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"io"
+)
+
+type AuthContext struct {
+	Contexts []AuthContextInfo
+}
+
+type AuthContextInfo struct {
+	Name     string `json:"name"`
+	Current  bool   `json:"current"`
+	Email    string `json:"email,omitempty"`
+	HubURL   string `json:"hub_url,omitempty"`
+	LoggedIn bool   `json:"logged_in"`
+}
+
+var _ Displayable = &AuthContext{}
+
+func (a *AuthContext) JSON(out io.Writer) error {
+	return writeJSON(a.Contexts, out)
+}
+
+func (a *AuthContext) Cols() []string {
+	cols := []string{
+		"Current", "Name", "Email", "HubURL", "LoggedIn",
+	}
+	return cols
+}
+
+func (a *AuthContext) ColMap() map[string]string {
+	return map[string]string{
+		"Current": "Current", "Name": "Name", "Email": "Email",
+		"HubURL": "Hub URL", "LoggedIn": "Logged In",
+	}
+}
+
+func (a *AuthContext) KV() []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, c := range a.Contexts {
+		current := ""
+		if c.Current {
+			current = "*"
+		}
+		m := map[string]interface{}{
+			"Current": current, "Name": c.Name, "Email": c.Email,
+			"HubURL": c.HubURL, "LoggedIn": c.LoggedIn,
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import "io"
+
+// The results of an item of a batch.
+const (
+	BatchSucceeded = "ok"
+	BatchFailed    = "failed"
+	BatchSkipped   = "skipped"
+)
+
+// BatchResultInfo is the outcome of a batch operation on one item.
+type BatchResultInfo struct {
+	Item   string `json:"item"`
+	Action string `json:"action"`
+	ID     string `json:"id,omitempty"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BatchResult struct {
+	Results []BatchResultInfo
+}
+
+var _ Displayable = &BatchResult{}
+
+func (b *BatchResult) JSON(out io.Writer) error {
+	return writeJSON(b.Results, out)
+}
+
+func (b *BatchResult) Cols() []string {
+	cols := []string{
+		"Item", "Action", "ID", "Result", "Error",
+	}
+	return cols
+}
+
+func (b *BatchResult) ColMap() map[string]string {
+	return map[string]string{
+		"Item": "Item", "Action": "Action", "ID": "ID", "Result": "Result", "Error": "Error",
+	}
+}
+
+func (b *BatchResult) KV() []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, r := range b.Results {
+		m := map[string]interface{}{
+			"Item": r.Item, "Action": r.Action, "ID": r.ID, "Result": r.Result, "Error": r.Error,
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
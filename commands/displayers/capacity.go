@@ -0,0 +1,286 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/Ankr-network/ankrctl/types"
+	"github.com/fatih/color"
+)
+
+// usageBarWidth is the number of cells of a utilization bar.
+const usageBarWidth = 10
+
+var (
+	usageLow  = color.New(color.FgGreen).SprintFunc()
+	usageMid  = color.New(color.FgYellow).SprintFunc()
+	usageHigh = color.New(color.FgRed).SprintFunc()
+)
+
+// ResourceUsage is the used and total amount of a cluster resource, in vCPUs
+// for CPU and GiB for memory and storage.
+type ResourceUsage struct {
+	Used    float64 `json:"used"`
+	Total   float64 `json:"total"`
+	Free    float64 `json:"free"`
+	Percent float64 `json:"percent"`
+}
+
+// NewResourceUsage computes the free amount and utilization of a resource.
+func NewResourceUsage(used, total float64) ResourceUsage {
+	u := ResourceUsage{Used: used, Total: total, Free: math.Max(total-used, 0)}
+	if total > 0 {
+		u.Percent = used / total * 100
+	}
+
+	return u
+}
+
+// ClusterUsageInfo is the capacity of a cluster decoded from its heartbeat.
+type ClusterUsageInfo struct {
+	ID            string        `json:"id"`
+	Name          string        `json:"name"`
+	Status        string        `json:"status"`
+	Lat           string        `json:"lat,omitempty"`
+	Lng           string        `json:"lng,omitempty"`
+	WalletAddress string        `json:"wallet_address,omitempty"`
+	CPU           ResourceUsage `json:"cpu"`
+	Memory        ResourceUsage `json:"memory"`
+	Storage       ResourceUsage `json:"storage"`
+	Endpoints     int64         `json:"endpoints"`
+	Images        int64         `json:"images"`
+	NetworkIO     int64         `json:"network_io"`
+}
+
+// MaxPercent returns the utilization of the busiest resource.
+func (u ClusterUsageInfo) MaxPercent() float64 {
+	return math.Max(u.CPU.Percent, math.Max(u.Memory.Percent, u.Storage.Percent))
+}
+
+type ClusterUsage struct {
+	Usages []ClusterUsageInfo
+
+	// Color colours the usage bars, for tables written to a terminal.
+	Color bool
+}
+
+var _ Filterable = &ClusterUsage{}
+var _ Sortable = &ClusterUsage{}
+var _ Wide = &ClusterUsage{}
+
+func (c *ClusterUsage) JSON(out io.Writer) error {
+	return writeJSON(c.Usages, out)
+}
+
+func (c *ClusterUsage) Cols() []string {
+	cols := []string{
+		"ID", "Name", "Status", "CPU", "CPU%", "FreeCPU", "Memory", "Memory%", "FreeMemory",
+		"Storage", "Storage%", "FreeStorage", "Endpoints", "Usage",
+	}
+	return cols
+}
+
+// WideCols keeps the usage bar last, so that its colours don't shift the
+// columns after it.
+func (c *ClusterUsage) WideCols() []string {
+	cols := []string{
+		"ID", "Name", "Status", "CPU", "CPU%", "FreeCPU", "Memory", "Memory%", "FreeMemory",
+		"Storage", "Storage%", "FreeStorage", "Endpoints", "Images", "NetworkIO", "Usage",
+	}
+	return cols
+}
+
+func (c *ClusterUsage) ColMap() map[string]string {
+	return map[string]string{
+		"ID": "ID", "Name": "Name", "Status": "Status",
+		"CPU": "CPU (vCPU)", "CPU%": "CPU%", "FreeCPU": "Free CPU",
+		"Memory": "Memory (GiB)", "Memory%": "Memory%", "FreeMemory": "Free Memory",
+		"Storage": "Storage (GiB)", "Storage%": "Storage%", "FreeStorage": "Free Storage",
+		"Endpoints": "Endpoints", "Usage": "Usage", "Images": "Images", "NetworkIO": "Network IO",
+	}
+}
+
+func (c *ClusterUsage) Filter(keep func(i int) bool) {
+	kept := []ClusterUsageInfo{}
+	for i, item := range c.Usages {
+		if keep(i) {
+			kept = append(kept, item)
+		}
+	}
+
+	c.Usages = kept
+}
+
+func (c *ClusterUsage) Reorder(order []int) {
+	sorted := make([]ClusterUsageInfo, len(order))
+	for i, o := range order {
+		sorted[i] = c.Usages[o]
+	}
+
+	c.Usages = sorted
+}
+
+func (c *ClusterUsage) KV() []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, u := range c.Usages {
+		m := map[string]interface{}{
+			"ID": u.ID, "Name": u.Name, "Status": u.Status,
+			"CPU": usedOfTotal(u.CPU), "CPU%": percent(u.CPU), "FreeCPU": types.FormatCPU(u.CPU.Free),
+			"Memory": usedOfTotal(u.Memory), "Memory%": percent(u.Memory), "FreeMemory": types.FormatSize(u.Memory.Free),
+			"Storage": usedOfTotal(u.Storage), "Storage%": percent(u.Storage), "FreeStorage": types.FormatSize(u.Storage.Free),
+			"Endpoints": u.Endpoints, "Usage": usageBar(u.MaxPercent(), c.Color), "Images": u.Images, "NetworkIO": u.NetworkIO,
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
+
+// ClusterResources shows the usage of each resource of one cluster.
+type ClusterResources struct {
+	Usage ClusterUsageInfo
+
+	// Color colours the usage bars, for tables written to a terminal.
+	Color bool
+}
+
+var _ Displayable = &ClusterResources{}
+
+func (c *ClusterResources) JSON(out io.Writer) error {
+	return writeJSON(c.Usage, out)
+}
+
+func (c *ClusterResources) Cols() []string {
+	cols := []string{
+		"Cluster", "Resource", "Used", "Total", "Free", "Percent", "Usage",
+	}
+	return cols
+}
+
+func (c *ClusterResources) ColMap() map[string]string {
+	return map[string]string{
+		"Cluster": "Cluster", "Resource": "Resource", "Used": "Used", "Total": "Total",
+		"Free": "Free", "Percent": "Usage%", "Usage": "Usage",
+	}
+}
+
+func (c *ClusterResources) KV() []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, r := range []struct {
+		name   string
+		format func(float64) string
+		usage  ResourceUsage
+	}{
+		{"CPU", types.FormatCPU, c.Usage.CPU},
+		{"Memory", types.FormatSize, c.Usage.Memory},
+		{"Storage", types.FormatSize, c.Usage.Storage},
+	} {
+		m := map[string]interface{}{
+			"Cluster": c.Usage.Name, "Resource": r.name,
+			"Used":    r.format(r.usage.Used),
+			"Total":   r.format(r.usage.Total),
+			"Free":    r.format(r.usage.Free),
+			"Percent": percent(r.usage), "Usage": usageBar(r.usage.Percent, c.Color),
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
+
+func usedOfTotal(u ResourceUsage) string {
+	return fmt.Sprintf("%.2f/%.2f", u.Used, u.Total)
+}
+
+func percent(u ResourceUsage) string {
+	return fmt.Sprintf("%.1f%%", u.Percent)
+}
+
+// usageBar draws a utilization bar. Coloured bars are green below 60%, yellow
+// below 85% and red above.
+func usageBar(pct float64, colored bool) string {
+	filled := int(math.Round(math.Min(math.Max(pct, 0), 100) / 100 * usageBarWidth))
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", usageBarWidth-filled)
+	if !colored || color.NoColor {
+		return bar
+	}
+
+	switch {
+	case pct >= 85:
+		return usageHigh(bar)
+	case pct >= 60:
+		return usageMid(bar)
+	}
+
+	return usageLow(bar)
+}
+
+// ClusterCandidateInfo is a cluster ranked for a namespace request.
+type ClusterCandidateInfo struct {
+	Rank     int              `json:"rank"`
+	Usage    ClusterUsageInfo `json:"cluster"`
+	Fits     bool             `json:"fits"`
+	Headroom float64          `json:"headroom_percent"`
+	Distance *float64         `json:"distance_km,omitempty"`
+}
+
+type ClusterRecommendation struct {
+	Candidates []ClusterCandidateInfo
+}
+
+var _ Displayable = &ClusterRecommendation{}
+
+func (r *ClusterRecommendation) JSON(out io.Writer) error {
+	return writeJSON(r.Candidates, out)
+}
+
+func (r *ClusterRecommendation) Cols() []string {
+	cols := []string{
+		"Rank", "ID", "Name", "Status", "FreeCPU", "FreeMemory", "FreeStorage", "Headroom", "Distance", "Fits",
+	}
+	return cols
+}
+
+func (r *ClusterRecommendation) ColMap() map[string]string {
+	return map[string]string{
+		"Rank": "Rank", "ID": "ID", "Name": "Name", "Status": "Status", "FreeCPU": "Free CPU",
+		"FreeMemory": "Free Memory", "FreeStorage": "Free Storage", "Headroom": "Headroom",
+		"Distance": "Distance", "Fits": "Fits",
+	}
+}
+
+func (r *ClusterRecommendation) KV() []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, c := range r.Candidates {
+		distance := "unknown"
+		if c.Distance != nil {
+			distance = fmt.Sprintf("%.0f km", *c.Distance)
+		}
+
+		m := map[string]interface{}{
+			"Rank": c.Rank, "ID": c.Usage.ID, "Name": c.Usage.Name, "Status": c.Usage.Status,
+			"FreeCPU":     types.FormatCPU(c.Usage.CPU.Free),
+			"FreeMemory":  types.FormatSize(c.Usage.Memory.Free),
+			"FreeStorage": types.FormatSize(c.Usage.Storage.Free),
+			"Headroom":    fmt.Sprintf("%.1f%%", c.Headroom), "Distance": distance, "Fits": c.Fits,
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
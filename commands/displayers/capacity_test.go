@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResourceUsage(t *testing.T) {
+	tests := []struct {
+		used, total float64
+		want        ResourceUsage
+	}{
+		{used: 1, total: 4, want: ResourceUsage{Used: 1, Total: 4, Free: 3, Percent: 25}},
+		{used: 0, total: 0, want: ResourceUsage{}},
+		{used: 2, total: 0, want: ResourceUsage{Used: 2}},
+		{used: 6, total: 4, want: ResourceUsage{Used: 6, Total: 4, Free: 0, Percent: 150}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, NewResourceUsage(tt.used, tt.total), "%v of %v", tt.used, tt.total)
+	}
+}
+
+func TestUsageBar(t *testing.T) {
+	noColor := color.NoColor
+	defer func() { color.NoColor = noColor }()
+	color.NoColor = false
+
+	const (
+		green  = "\x1b[32m"
+		yellow = "\x1b[33m"
+		red    = "\x1b[31m"
+	)
+
+	tests := []struct {
+		pct    float64
+		filled int
+		color  string
+	}{
+		{pct: -5, filled: 0, color: green},
+		{pct: 0, filled: 0, color: green},
+		{pct: 59.9, filled: 6, color: green},
+		{pct: 60, filled: 6, color: yellow},
+		{pct: 84.9, filled: 8, color: yellow},
+		{pct: 85, filled: 9, color: red},
+		{pct: 150, filled: 10, color: red},
+	}
+
+	for _, tt := range tests {
+		got := usageBar(tt.pct, true)
+		assert.True(t, strings.HasPrefix(got, tt.color), "usageBar(%v) = %q, want color %q", tt.pct, got, tt.color)
+		assert.Equal(t, tt.filled, strings.Count(got, "█"), "filled cells of usageBar(%v)", tt.pct)
+		assert.Equal(t, usageBarWidth, strings.Count(got, "█")+strings.Count(got, "░"), "cells of usageBar(%v)", tt.pct)
+	}
+
+	assert.Equal(t, "█████████░", usageBar(90, false))
+	color.NoColor = true
+	assert.Equal(t, "█████████░", usageBar(90, true), "colours are off")
+}
+
+func TestClusterUsageWideCols(t *testing.T) {
+	item := &ClusterUsage{}
+	cols := item.WideCols()
+	assert.Equal(t, "Usage", cols[len(cols)-1], "the usage bar should be last")
+	assert.Len(t, cols, len(item.ColMap()), "every column should be shown")
+	for _, col := range cols {
+		assert.NotEmpty(t, item.ColMap()[col], "column %q has no header", col)
+	}
+}
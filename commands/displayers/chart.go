@@ -23,7 +23,8 @@ type Chart struct {
 	Charts []*pb.Chart
 }
 
-var _ Displayable = &Chart{}
+var _ Filterable = &Chart{}
+var _ Sortable = &Chart{}
 
 func (c *Chart) JSON(out io.Writer) error {
 	return writeJSON(c.Charts, out)
@@ -43,6 +44,26 @@ func (c *Chart) ColMap() map[string]string {
 	}
 }
 
+func (c *Chart) Filter(keep func(i int) bool) {
+	kept := []*pb.Chart{}
+	for i, item := range c.Charts {
+		if keep(i) {
+			kept = append(kept, item)
+		}
+	}
+
+	c.Charts = kept
+}
+
+func (c *Chart) Reorder(order []int) {
+	sorted := make([]*pb.Chart, len(order))
+	for i, o := range order {
+		sorted[i] = c.Charts[o]
+	}
+
+	c.Charts = sorted
+}
+
 func (c *Chart) KV() []map[string]interface{} {
 	out := []map[string]interface{}{}
 	for _, c := range c.Charts {
@@ -55,3 +76,178 @@ func (c *Chart) KV() []map[string]interface{} {
 
 	return out
 }
+
+type ChartDetail struct {
+	Detail ChartDetailInfo
+}
+
+type ChartDetailInfo struct {
+	Repo     string         `json:"repo"`
+	Name     string         `json:"name"`
+	Version  string         `json:"version,omitempty"`
+	Versions []ChartVersion `json:"versions"`
+	Readme   string         `json:"readme"`
+	Values   string         `json:"values"`
+}
+
+type ChartVersion struct {
+	Version    string `json:"version"`
+	AppVersion string `json:"app_version"`
+}
+
+var _ Displayable = &ChartDetail{}
+
+func (c *ChartDetail) JSON(out io.Writer) error {
+	return writeJSON(c.Detail, out)
+}
+
+func (c *ChartDetail) Cols() []string {
+	cols := []string{
+		"Repo", "Name", "Version", "AppVersion",
+	}
+	return cols
+}
+
+func (c *ChartDetail) ColMap() map[string]string {
+	return map[string]string{
+		"Repo": "Repo", "Name": "Name", "Version": "Version", "AppVersion": "App Version",
+	}
+}
+
+func (c *ChartDetail) KV() []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, v := range c.Detail.Versions {
+		m := map[string]interface{}{
+			"Repo": c.Detail.Repo, "Name": c.Detail.Name,
+			"Version": v.Version, "AppVersion": v.AppVersion,
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
+
+// Kinds of value change shown by ChartDiff.
+const (
+	ValueAdded   = "added"
+	ValueRemoved = "removed"
+	ValueChanged = "changed"
+)
+
+type ChartDiff struct {
+	Diff ChartDiffInfo
+}
+
+type ChartDiffInfo struct {
+	Name   string             `json:"name"`
+	From   ChartRef           `json:"from"`
+	To     ChartRef           `json:"to"`
+	Values []ChartValueChange `json:"values"`
+}
+
+type ChartRef struct {
+	Repo       string `json:"repo"`
+	Version    string `json:"version"`
+	AppVersion string `json:"app_version"`
+}
+
+type ChartValueChange struct {
+	Key    string `json:"key"`
+	Change string `json:"change"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+var _ Displayable = &ChartDiff{}
+
+func (c *ChartDiff) JSON(out io.Writer) error {
+	return writeJSON(c.Diff, out)
+}
+
+func (c *ChartDiff) Cols() []string {
+	cols := []string{
+		"Key", "Change", "From", "To",
+	}
+	return cols
+}
+
+func (c *ChartDiff) ColMap() map[string]string {
+	return map[string]string{
+		"Key": "Key", "Change": "Change", "From": "From", "To": "To",
+	}
+}
+
+func (c *ChartDiff) KV() []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, v := range c.Diff.Values {
+		m := map[string]interface{}{
+			"Key": v.Key, "Change": v.Change, "From": v.From, "To": v.To,
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
+
+type ChartVersions struct {
+	Versions []ChartVersionInfo
+}
+
+type ChartVersionInfo struct {
+	Repo       string `json:"repo"`
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	AppVersion string `json:"app_version"`
+}
+
+var _ Filterable = &ChartVersions{}
+var _ Sortable = &ChartVersions{}
+
+func (c *ChartVersions) JSON(out io.Writer) error {
+	return writeJSON(c.Versions, out)
+}
+
+func (c *ChartVersions) Cols() []string {
+	cols := []string{
+		"Repo", "Name", "Version", "AppVersion",
+	}
+	return cols
+}
+
+func (c *ChartVersions) ColMap() map[string]string {
+	return map[string]string{
+		"Repo": "Repo", "Name": "Name", "Version": "Version", "AppVersion": "App Version",
+	}
+}
+
+func (c *ChartVersions) Filter(keep func(i int) bool) {
+	kept := []ChartVersionInfo{}
+	for i, item := range c.Versions {
+		if keep(i) {
+			kept = append(kept, item)
+		}
+	}
+
+	c.Versions = kept
+}
+
+func (c *ChartVersions) Reorder(order []int) {
+	sorted := make([]ChartVersionInfo, len(order))
+	for i, o := range order {
+		sorted[i] = c.Versions[o]
+	}
+
+	c.Versions = sorted
+}
+
+func (c *ChartVersions) KV() []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, v := range c.Versions {
+		m := map[string]interface{}{
+			"Repo": v.Repo, "Name": v.Name, "Version": v.Version, "AppVersion": v.AppVersion,
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
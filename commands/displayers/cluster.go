@@ -15,10 +15,10 @@ package displayers
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
 	"strings"
 
+	"github.com/Ankr-network/ankrctl/types"
 	common "github.com/Ankr-network/dccn-common/protos/common"
 )
 
@@ -38,7 +38,9 @@ type Metrics struct {
 	NetworkIO     int64
 }
 
-var _ Displayable = &Cluster{}
+var _ Filterable = &Cluster{}
+var _ Sortable = &Cluster{}
+var _ Wide = &Cluster{}
 
 func (c *Cluster) JSON(out io.Writer) error {
 	return writeJSON(c.Clusters, out)
@@ -51,11 +53,37 @@ func (c *Cluster) Cols() []string {
 	return cols
 }
 
+func (c *Cluster) WideCols() []string {
+	return append(c.Cols(), "UsedCPU", "UsedMEM", "UsedStorage", "Images", "Endpoints")
+}
+
 func (c *Cluster) ColMap() map[string]string {
 	return map[string]string{
 		"ID": "ID", "Name": "Name", "CPU": "CPU", "MEM": "Memory", "Storage": "Storage",
 		"Lat": "Latitude", "Lng": "Longitude", "Status": "Status", "WalletAddress": "WalletAddress",
+		"UsedCPU": "Used CPU", "UsedMEM": "Used Memory", "UsedStorage": "Used Storage",
+		"Images": "Images", "Endpoints": "Endpoints",
+	}
+}
+
+func (c *Cluster) Filter(keep func(i int) bool) {
+	kept := []common.DataCenterStatus{}
+	for i, item := range c.Clusters {
+		if keep(i) {
+			kept = append(kept, item)
+		}
+	}
+
+	c.Clusters = kept
+}
+
+func (c *Cluster) Reorder(order []int) {
+	sorted := make([]common.DataCenterStatus, len(order))
+	for i, o := range order {
+		sorted[i] = c.Clusters[o]
 	}
+
+	c.Clusters = sorted
 }
 
 func (c *Cluster) KV() []map[string]interface{} {
@@ -64,14 +92,60 @@ func (c *Cluster) KV() []map[string]interface{} {
 		metrics := Metrics{}
 		_ = json.Unmarshal([]byte(c.DcHeartbeatReport.Metrics), &metrics)
 		m := map[string]interface{}{
-			"ID": c.DcId, "Name": c.DcName, "CPU": fmt.Sprintf("%.2f", float64(metrics.TotalCPU)/1000) + "vCPU(s)",
-			"MEM":     fmt.Sprintf("%.2f", float64(metrics.TotalMemory)/1024) + "GiB",
-			"Storage": fmt.Sprintf("%.2f", float64(metrics.TotalStorage)/1024) + "GiB",
+			"ID": c.DcId, "Name": c.DcName, "CPU": types.FormatCPU(float64(metrics.TotalCPU) / types.MilliCPUPerCPU),
+			"MEM":     types.FormatSize(float64(metrics.TotalMemory) / types.MiBPerGiB),
+			"Storage": types.FormatSize(float64(metrics.TotalStorage) / types.MiBPerGiB),
 			"Lat":     c.GeoLocation.Lat, "Lng": c.GeoLocation.Lng, "Status": strings.ToLower(c.DcStatus.String()),
 			"WalletAddress": c.DcAttributes.WalletAddress,
+			"UsedCPU":       types.FormatCPU(float64(metrics.UsedCPU) / types.MilliCPUPerCPU),
+			"UsedMEM":       types.FormatSize(float64(metrics.UsedMemory) / types.MiBPerGiB),
+			"UsedStorage":   types.FormatSize(float64(metrics.UsedStorage) / types.MiBPerGiB),
+			"Images":        metrics.ImageCount, "Endpoints": metrics.EndPointCount,
 		}
 		out = append(out, m)
 	}
 
 	return out
 }
+
+type NetworkInfo struct {
+	Network NetworkStats
+}
+
+type NetworkStats struct {
+	UserCount      int64 `json:"user_count"`
+	HostCount      int64 `json:"host_count"`
+	NamespaceCount int64 `json:"namespace_count"`
+	ContainerCount int64 `json:"container_count"`
+	Traffic        int64 `json:"traffic"`
+}
+
+var _ Displayable = &NetworkInfo{}
+
+func (n *NetworkInfo) JSON(out io.Writer) error {
+	return writeJSON(n.Network, out)
+}
+
+func (n *NetworkInfo) Cols() []string {
+	cols := []string{
+		"Users", "Hosts", "Namespaces", "Containers", "Traffic",
+	}
+	return cols
+}
+
+func (n *NetworkInfo) ColMap() map[string]string {
+	return map[string]string{
+		"Users": "Users", "Hosts": "Hosts", "Namespaces": "Namespaces",
+		"Containers": "Containers", "Traffic": "Traffic",
+	}
+}
+
+func (n *NetworkInfo) KV() []map[string]interface{} {
+	m := map[string]interface{}{
+		"Users": n.Network.UserCount, "Hosts": n.Network.HostCount,
+		"Namespaces": n.Network.NamespaceCount, "Containers": n.Network.ContainerCount,
+		"Traffic": n.Network.Traffic,
+	}
+
+	return []map[string]interface{}{m}
+}
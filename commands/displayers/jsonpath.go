@@ -0,0 +1,596 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a parsed kubectl style JSONPath template, such as
+// {range .[*]}{.app_deployment.app_id}{"\n"}{end}. It supports fields,
+// indexes, slices, wildcards, recursive descent and simple filters.
+type jsonPath struct {
+	nodes []jpNode
+}
+
+type jpNodeKind int
+
+const (
+	jpText jpNodeKind = iota
+	jpPath
+	jpRange
+)
+
+type jpNode struct {
+	kind  jpNodeKind
+	text  string
+	path  []jpStep
+	root  bool
+	nodes []jpNode
+}
+
+type jpStepKind int
+
+const (
+	jpField jpStepKind = iota
+	jpWildcard
+	jpIndex
+	jpSlice
+	jpRecursive
+	jpFilter
+)
+
+type jpStep struct {
+	kind       jpStepKind
+	name       string
+	index, end int
+	hasStart   bool
+	hasEnd     bool
+	filter     *jpCondition
+}
+
+// jpCondition is a filter such as ?(@.status == "running") or ?(@.endpoint).
+type jpCondition struct {
+	path  []jpStep
+	op    string
+	value interface{}
+}
+
+// parseJSONPath parses a JSONPath template.
+func parseJSONPath(tmpl string) (*jsonPath, error) {
+	nodes, rest, err := parseJPNodes(tmpl, false)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("jsonpath: unexpected {end}")
+	}
+
+	return &jsonPath{nodes: nodes}, nil
+}
+
+// parseJPNodes parses tmpl up to the end of the input, or up to the {end}
+// closing a range when inRange is set, and returns what follows.
+func parseJPNodes(tmpl string, inRange bool) ([]jpNode, string, error) {
+	var nodes []jpNode
+
+	for tmpl != "" {
+		open := strings.Index(tmpl, "{")
+		if open < 0 {
+			nodes = append(nodes, jpNode{kind: jpText, text: tmpl})
+			tmpl = ""
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, jpNode{kind: jpText, text: tmpl[:open]})
+		}
+
+		end := closingBrace(tmpl[open:])
+		if end < 0 {
+			return nil, "", fmt.Errorf("jsonpath: unclosed { in %q", tmpl[open:])
+		}
+
+		expr := strings.TrimSpace(tmpl[open+1 : open+end])
+		tmpl = tmpl[open+end+1:]
+
+		switch {
+		case expr == "end":
+			if !inRange {
+				return nodes, "end", nil
+			}
+			return nodes, tmpl, nil
+		case strings.HasPrefix(expr, "range "):
+			root, path, err := parseJPPath(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+
+			body, rest, err := parseJPNodes(tmpl, true)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jpNode{kind: jpRange, root: root, path: path, nodes: body})
+			tmpl = rest
+		case strings.HasPrefix(expr, `"`):
+			text, err := strconv.Unquote(expr)
+			if err != nil {
+				return nil, "", fmt.Errorf("jsonpath: invalid string %s", expr)
+			}
+			nodes = append(nodes, jpNode{kind: jpText, text: text})
+		default:
+			root, path, err := parseJPPath(expr)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jpNode{kind: jpPath, root: root, path: path})
+		}
+	}
+
+	if inRange {
+		return nil, "", fmt.Errorf("jsonpath: {range} without {end}")
+	}
+
+	return nodes, "", nil
+}
+
+// closingBrace returns the index of the } closing the { s starts with,
+// skipping braces in quoted strings.
+func closingBrace(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote != 0 && s[i] == '\\':
+			i++
+		case quote != 0 && s[i] == quote:
+			quote = 0
+		case quote != 0:
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == '}':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// parseJPPath parses a path such as $.a[0].b, .a[*] or @.a. root reports
+// whether the path starts from the document root rather than the current
+// range element.
+func parseJPPath(expr string) (root bool, steps []jpStep, err error) {
+	p := expr
+	switch {
+	case strings.HasPrefix(p, "$"):
+		root, p = true, p[1:]
+	case strings.HasPrefix(p, "@"):
+		p = p[1:]
+	}
+
+	for p != "" {
+		switch {
+		case strings.HasPrefix(p, ".."):
+			steps = append(steps, jpStep{kind: jpRecursive})
+			p = p[1:]
+		case strings.HasPrefix(p, ".*"):
+			steps = append(steps, jpStep{kind: jpWildcard})
+			p = p[2:]
+		case p == ".":
+			p = ""
+		case strings.HasPrefix(p, ".["):
+			p = p[1:]
+		case p[0] == '.':
+			n := 1
+			for n < len(p) && p[n] != '.' && p[n] != '[' {
+				n++
+			}
+			if n == 1 {
+				return false, nil, fmt.Errorf("jsonpath: missing field name in %q", expr)
+			}
+			steps = append(steps, jpStep{kind: jpField, name: p[1:n]})
+			p = p[n:]
+		case p[0] == '[':
+			end := closingBracket(p)
+			if end < 0 {
+				return false, nil, fmt.Errorf("jsonpath: unclosed [ in %q", expr)
+			}
+
+			step, err := parseJPBracket(strings.TrimSpace(p[1:end]))
+			if err != nil {
+				return false, nil, err
+			}
+			steps = append(steps, step)
+			p = p[end+1:]
+		default:
+			// a bare field, such as the first one of a[0]
+			n := 0
+			for n < len(p) && p[n] != '.' && p[n] != '[' {
+				n++
+			}
+			steps = append(steps, jpStep{kind: jpField, name: p[:n]})
+			p = p[n:]
+		}
+	}
+
+	return root, steps, nil
+}
+
+func closingBracket(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0 && s[i] == quote:
+			quote = 0
+		case quote != 0:
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == '[':
+			depth++
+		case s[i] == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func parseJPBracket(s string) (jpStep, error) {
+	switch {
+	case s == "*":
+		return jpStep{kind: jpWildcard}, nil
+	case strings.HasPrefix(s, "?(") && strings.HasSuffix(s, ")"):
+		cond, err := parseJPCondition(strings.TrimSpace(s[2 : len(s)-1]))
+		if err != nil {
+			return jpStep{}, err
+		}
+		return jpStep{kind: jpFilter, filter: cond}, nil
+	case len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]:
+		return jpStep{kind: jpField, name: s[1 : len(s)-1]}, nil
+	case strings.Contains(s, ":"):
+		parts := strings.SplitN(s, ":", 2)
+		step := jpStep{kind: jpSlice}
+		var err error
+		if p := strings.TrimSpace(parts[0]); p != "" {
+			if step.index, err = strconv.Atoi(p); err != nil {
+				return jpStep{}, fmt.Errorf("jsonpath: invalid slice [%s]", s)
+			}
+			step.hasStart = true
+		}
+		if p := strings.TrimSpace(parts[1]); p != "" {
+			if step.end, err = strconv.Atoi(p); err != nil {
+				return jpStep{}, fmt.Errorf("jsonpath: invalid slice [%s]", s)
+			}
+			step.hasEnd = true
+		}
+		return step, nil
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return jpStep{}, fmt.Errorf("jsonpath: invalid index [%s]", s)
+	}
+
+	return jpStep{kind: jpIndex, index: i}, nil
+}
+
+func parseJPCondition(s string) (*jpCondition, error) {
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		i := strings.Index(s, op)
+		if i < 0 {
+			continue
+		}
+
+		_, path, err := parseJPPath(strings.TrimSpace(s[:i]))
+		if err != nil {
+			return nil, err
+		}
+
+		lit := strings.TrimSpace(s[i+len(op):])
+		var value interface{}
+		switch {
+		case len(lit) >= 2 && (lit[0] == '\'' || lit[0] == '"') && lit[len(lit)-1] == lit[0]:
+			value = lit[1 : len(lit)-1]
+		case lit == "true" || lit == "false":
+			value = lit == "true"
+		default:
+			f, err := strconv.ParseFloat(lit, 64)
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath: invalid value %q in filter", lit)
+			}
+			value = f
+		}
+
+		return &jpCondition{path: path, op: op, value: value}, nil
+	}
+
+	_, path, err := parseJPPath(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jpCondition{path: path}, nil
+}
+
+// Execute writes the template evaluated against data, which is a document
+// decoded from JSON.
+func (j *jsonPath) Execute(w io.Writer, data interface{}) error {
+	return execJPNodes(w, j.nodes, data, data)
+}
+
+func execJPNodes(w io.Writer, nodes []jpNode, root, cur interface{}) error {
+	for _, n := range nodes {
+		start := cur
+		if n.root {
+			start = root
+		}
+
+		switch n.kind {
+		case jpText:
+			if _, err := io.WriteString(w, n.text); err != nil {
+				return err
+			}
+		case jpPath:
+			var out []string
+			for _, v := range evalJPPath(n.path, []interface{}{start}) {
+				s, err := jpString(v)
+				if err != nil {
+					return err
+				}
+				out = append(out, s)
+			}
+			if _, err := io.WriteString(w, strings.Join(out, " ")); err != nil {
+				return err
+			}
+		case jpRange:
+			for _, v := range evalJPPath(n.path, []interface{}{start}) {
+				items := []interface{}{v}
+				if a, ok := v.([]interface{}); ok && !endsInSelection(n.path) {
+					items = a
+				}
+				for _, item := range items {
+					if err := execJPNodes(w, n.nodes, root, item); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// endsInSelection reports whether a path already yields the elements of a
+// list rather than the list itself.
+func endsInSelection(path []jpStep) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	switch path[len(path)-1].kind {
+	case jpWildcard, jpSlice, jpFilter:
+		return true
+	}
+
+	return false
+}
+
+func evalJPPath(steps []jpStep, values []interface{}) []interface{} {
+	for _, step := range steps {
+		var next []interface{}
+		for _, v := range values {
+			if step.kind == jpRecursive {
+				next = append(next, descendants(v)...)
+				continue
+			}
+			next = append(next, evalJPStep(step, v)...)
+		}
+
+		values = next
+	}
+
+	return values
+}
+
+func evalJPStep(step jpStep, v interface{}) []interface{} {
+	switch step.kind {
+	case jpField:
+		if m, ok := v.(map[string]interface{}); ok {
+			if f, ok := m[step.name]; ok {
+				return []interface{}{f}
+			}
+		}
+	case jpWildcard:
+		switch t := v.(type) {
+		case []interface{}:
+			return t
+		case map[string]interface{}:
+			var out []interface{}
+			for _, k := range sortedKeys(t) {
+				out = append(out, t[k])
+			}
+			return out
+		}
+	case jpIndex:
+		if a, ok := v.([]interface{}); ok {
+			i := step.index
+			if i < 0 {
+				i += len(a)
+			}
+			if i >= 0 && i < len(a) {
+				return []interface{}{a[i]}
+			}
+		}
+	case jpSlice:
+		if a, ok := v.([]interface{}); ok {
+			start, end := 0, len(a)
+			if step.hasStart {
+				start = clampIndex(step.index, len(a))
+			}
+			if step.hasEnd {
+				end = clampIndex(step.end, len(a))
+			}
+			if start < end {
+				return a[start:end]
+			}
+		}
+	case jpFilter:
+		var items []interface{}
+		switch t := v.(type) {
+		case []interface{}:
+			items = t
+		case map[string]interface{}:
+			for _, k := range sortedKeys(t) {
+				items = append(items, t[k])
+			}
+		}
+
+		var out []interface{}
+		for _, item := range items {
+			if step.filter.match(item) {
+				out = append(out, item)
+			}
+		}
+		return out
+	}
+
+	return nil
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > n {
+		return n
+	}
+
+	return i
+}
+
+// descendants returns v and everything nested in it, for recursive descent.
+func descendants(v interface{}) []interface{} {
+	out := []interface{}{v}
+	switch t := v.(type) {
+	case []interface{}:
+		for _, e := range t {
+			out = append(out, descendants(e)...)
+		}
+	case map[string]interface{}:
+		for _, k := range sortedKeys(t) {
+			out = append(out, descendants(t[k])...)
+		}
+	}
+
+	return out
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (c *jpCondition) match(item interface{}) bool {
+	values := evalJPPath(c.path, []interface{}{item})
+	if c.op == "" {
+		return len(values) > 0
+	}
+
+	for _, v := range values {
+		if compareJP(v, c.op, c.value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compareJP(v interface{}, op string, want interface{}) bool {
+	if f, ok := want.(float64); ok {
+		got, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil {
+			return op == "!="
+		}
+
+		switch op {
+		case "==":
+			return got == f
+		case "!=":
+			return got != f
+		case "<":
+			return got < f
+		case "<=":
+			return got <= f
+		case ">":
+			return got > f
+		case ">=":
+			return got >= f
+		}
+	}
+
+	got, s := fmt.Sprint(v), fmt.Sprint(want)
+	switch op {
+	case "==":
+		return got == s
+	case "!=":
+		return got != s
+	case "<":
+		return got < s
+	case "<=":
+		return got <= s
+	case ">":
+		return got > s
+	case ">=":
+		return got >= s
+	}
+
+	return false
+}
+
+// jpString formats a value the way kubectl does: strings and numbers as
+// they are, everything else as JSON.
+func jpString(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case json.Number:
+		return t.String(), nil
+	case nil:
+		return "", nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONPath(t *testing.T) {
+	doc := `[
+		{"name": "web-1", "status": "running", "cpu": 500, "ready": true, "endpoints": ["a", "b"]},
+		{"name": "web-2", "status": "failed", "cpu": 1500, "ready": false, "endpoints": []},
+		{"name": "db-1", "status": "running", "cpu": 2000, "meta": {"name": "inner", "labels": {"tier": "db"}}}
+	]`
+
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+	var data interface{}
+	if !assert.NoError(t, dec.Decode(&data)) {
+		return
+	}
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{name: "index", tmpl: `{.[0].name}`, want: "web-1"},
+		{name: "negative index", tmpl: `{$[-1].name}`, want: "db-1"},
+		{name: "index out of range", tmpl: `{.[3].name}`, want: ""},
+		{name: "negative index out of range", tmpl: `{.[-4].name}`, want: ""},
+		{name: "wildcard", tmpl: `{.[*].name}`, want: "web-1 web-2 db-1"},
+		{name: "map wildcard", tmpl: `{.[2].meta.*}`, want: `{"tier":"db"} inner`},
+		{name: "quoted field", tmpl: `{.[2].meta['name']}`, want: "inner"},
+		{name: "list", tmpl: `{.[0].endpoints}`, want: `["a","b"]`},
+		{name: "bool", tmpl: `{.[1].ready}`, want: "false"},
+		{name: "missing field", tmpl: `{.[0].missing}`, want: ""},
+
+		{name: "slice", tmpl: `{.[0:2].cpu}`, want: "500 1500"},
+		{name: "slice from", tmpl: `{.[1:].name}`, want: "web-2 db-1"},
+		{name: "slice to", tmpl: `{.[:1].name}`, want: "web-1"},
+		{name: "negative slice start", tmpl: `{.[-2:].name}`, want: "web-2 db-1"},
+		{name: "negative slice end", tmpl: `{.[:-1].name}`, want: "web-1 web-2"},
+		{name: "negative slice", tmpl: `{.[-3:-2].name}`, want: "web-1"},
+		{name: "slice clamped", tmpl: `{.[-10:10].name}`, want: "web-1 web-2 db-1"},
+		{name: "empty slice", tmpl: `{.[2:1].name}`, want: ""},
+
+		{name: "filter equal", tmpl: `{.[?(@.status=="running")].name}`, want: "web-1 db-1"},
+		{name: "filter single quotes", tmpl: `{.[?(@.status == 'failed')].name}`, want: "web-2"},
+		{name: "filter not equal", tmpl: `{.[?(@.status!="running")].name}`, want: "web-2"},
+		{name: "filter greater", tmpl: `{.[?(@.cpu > 1000)].name}`, want: "web-2 db-1"},
+		{name: "filter greater or equal", tmpl: `{.[?(@.cpu >= 1500)].name}`, want: "web-2 db-1"},
+		{name: "filter less", tmpl: `{.[?(@.cpu < 1500)].name}`, want: "web-1"},
+		{name: "filter less or equal", tmpl: `{.[?(@.cpu <= 1500)].name}`, want: "web-1 web-2"},
+		{name: "filter bool", tmpl: `{.[?(@.ready == true)].name}`, want: "web-1"},
+		{name: "filter exists", tmpl: `{.[?(@.meta)].meta.name}`, want: "inner"},
+		{name: "filter nested exists", tmpl: `{.[?(@.endpoints[0])].name}`, want: "web-1"},
+		{name: "filter no match", tmpl: `{.[?(@.status=="pending")].name}`, want: ""},
+
+		{name: "recursive", tmpl: `{..meta.name}`, want: "inner"},
+		{name: "recursive field", tmpl: `{..name}`, want: "web-1 web-2 db-1 inner"},
+		{name: "recursive nested", tmpl: `{$..labels.tier}`, want: "db"},
+		{name: "recursive index", tmpl: `{..endpoints[0]}`, want: "a"},
+		{name: "recursive filter", tmpl: `{..[?(@.tier=="db")].tier}`, want: "db"},
+
+		{name: "range", tmpl: `{range .[*]}{.name}={.status}{"\n"}{end}`, want: "web-1=running\nweb-2=failed\ndb-1=running\n"},
+		{name: "range list", tmpl: `names: {range .}[{.name}]{end}`, want: "names: [web-1][web-2][db-1]"},
+		{name: "range filter", tmpl: `{range .[?(@.cpu > 1000)]}{.name}{"\t"}{$[0].name}{"\n"}{end}`, want: "web-2\tweb-1\ndb-1\tweb-1\n"},
+		{name: "nested range", tmpl: `{range .[0:1]}{range .endpoints[*]}<{@}>{end}{end}`, want: "<a><b>"},
+		{name: "braces in strings", tmpl: `{"{"}{.[0].name}{"}"}`, want: "{web-1}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jp, err := parseJSONPath(tt.tmpl)
+			if !assert.NoError(t, err, tt.tmpl) {
+				return
+			}
+
+			var out bytes.Buffer
+			if assert.NoError(t, jp.Execute(&out, data), tt.tmpl) {
+				assert.Equal(t, tt.want, out.String(), tt.tmpl)
+			}
+		})
+	}
+}
+
+func TestJSONPathMalformed(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		err  string
+	}{
+		{tmpl: `{.name`, err: "unclosed {"},
+		{tmpl: `{"}`, err: "unclosed {"},
+		{tmpl: `{range .}{.name}`, err: "{range} without {end}"},
+		{tmpl: `{.name}{end}`, err: "unexpected {end}"},
+		{tmpl: `{.[x]}`, err: "invalid index [x]"},
+		{tmpl: `{.[0}`, err: "unclosed ["},
+		{tmpl: `{.[1:x]}`, err: "invalid slice [1:x]"},
+		{tmpl: `{.[x:1]}`, err: "invalid slice [x:1]"},
+		{tmpl: `{.[?(@.status == running)]}`, err: `invalid value "running" in filter`},
+		{tmpl: `{.[?(@.status]}`, err: "invalid index"},
+		{tmpl: `{.a.[?(@.b[)]}`, err: "unclosed ["},
+		{tmpl: `{"\q"}`, err: "invalid string"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseJSONPath(tt.tmpl)
+		if assert.Error(t, err, tt.tmpl) {
+			assert.Contains(t, err.Error(), tt.err, tt.tmpl)
+		}
+	}
+}
@@ -27,7 +27,8 @@ type KeyStore struct {
 	PublicKey string `json:"publickey"`
 }
 
-var _ Displayable = &Key{}
+var _ Filterable = &Key{}
+var _ Sortable = &Key{}
 
 func (c *Key) JSON(out io.Writer) error {
 	return writeJSON(c.Keystores, out)
@@ -47,6 +48,26 @@ func (c *Key) ColMap() map[string]string {
 	}
 }
 
+func (c *Key) Filter(keep func(i int) bool) {
+	kept := []*KeyStore{}
+	for i, item := range c.Keystores {
+		if keep(i) {
+			kept = append(kept, item)
+		}
+	}
+
+	c.Keystores = kept
+}
+
+func (c *Key) Reorder(order []int) {
+	sorted := make([]*KeyStore, len(order))
+	for i, o := range order {
+		sorted[i] = c.Keystores[o]
+	}
+
+	c.Keystores = sorted
+}
+
 func (c *Key) KV() []map[string]interface{} {
 	out := []map[string]interface{}{}
 	for _, c := range c.Keystores {
@@ -14,9 +14,10 @@ limitations under the License.
 package displayers
 
 import (
-	"fmt"
 	"io"
 
+	"github.com/Ankr-network/ankrctl/types"
+
 	common "github.com/Ankr-network/dccn-common/protos/common"
 )
 
@@ -24,7 +25,8 @@ type Namespace struct {
 	Namespaces []common.NamespaceReport
 }
 
-var _ Displayable = &Namespace{}
+var _ Filterable = &Namespace{}
+var _ Sortable = &Namespace{}
 
 func (n *Namespace) JSON(out io.Writer) error {
 	return writeJSON(n.Namespaces, out)
@@ -45,14 +47,34 @@ func (n *Namespace) ColMap() map[string]string {
 	}
 }
 
+func (n *Namespace) Filter(keep func(i int) bool) {
+	kept := []common.NamespaceReport{}
+	for i, item := range n.Namespaces {
+		if keep(i) {
+			kept = append(kept, item)
+		}
+	}
+
+	n.Namespaces = kept
+}
+
+func (n *Namespace) Reorder(order []int) {
+	sorted := make([]common.NamespaceReport, len(order))
+	for i, o := range order {
+		sorted[i] = n.Namespaces[o]
+	}
+
+	n.Namespaces = sorted
+}
+
 func (n *Namespace) KV() []map[string]interface{} {
 	out := []map[string]interface{}{}
 	for _, n := range n.Namespaces {
 		m := map[string]interface{}{
 			"ID": n.Namespace.NsId, "Name": n.Namespace.NsName,
-			"CpuLimit":     fmt.Sprintf("%v vCPU(s)", float64(n.Namespace.NsCpuLimit)/1000),
-			"MemLimit":     fmt.Sprintf("%v GB", float64(n.Namespace.NsMemLimit)/1024),
-			"StorageLimit": fmt.Sprintf("%v GB", float64(n.Namespace.NsStorageLimit)/1024),
+			"CpuLimit":     types.FormatCPU(float64(n.Namespace.NsCpuLimit) / types.MilliCPUPerCPU),
+			"MemLimit":     types.FormatSize(float64(n.Namespace.NsMemLimit) / types.MiBPerGiB),
+			"StorageLimit": types.FormatSize(float64(n.Namespace.NsStorageLimit) / types.MiBPerGiB),
 			"ClusterID":    n.Namespace.ClusterId, "ClusterName": n.Namespace.ClusterName,
 			"Status": n.NsStatus.String(), "Event": n.NsEvent,
 		}
@@ -61,3 +83,116 @@ func (n *Namespace) KV() []map[string]interface{} {
 
 	return out
 }
+
+// NamespaceDetailInfo is a namespace with the apps running in it and the
+// capacity of its cluster.
+type NamespaceDetailInfo struct {
+	Namespace *common.NamespaceReport `json:"namespace"`
+	Apps      []*common.AppReport     `json:"apps"`
+	Cluster   *ClusterUsageInfo       `json:"cluster,omitempty"`
+}
+
+type NamespaceDetail struct {
+	Detail NamespaceDetailInfo
+}
+
+var _ Displayable = &NamespaceDetail{}
+
+func (n *NamespaceDetail) JSON(out io.Writer) error {
+	return writeJSON(n.Detail, out)
+}
+
+func (n *NamespaceDetail) Cols() []string {
+	return []string{
+		"ID", "Name", "ClusterName", "Status", "Event", "CpuLimit", "MemLimit", "StorageLimit", "Apps",
+	}
+}
+
+func (n *NamespaceDetail) ColMap() map[string]string {
+	return map[string]string{
+		"ID": "ID", "Name": "Name", "CpuLimit": "CPU Limit", "MemLimit": "Memory Limit",
+		"StorageLimit": "Storage Limit", "ClusterID": "Cluster ID", "ClusterName": "Cluster Name",
+		"Status": "Status", "Event": "Event", "Apps": "Apps",
+	}
+}
+
+func (n *NamespaceDetail) KV() []map[string]interface{} {
+	ns := n.Detail.Namespace
+	return []map[string]interface{}{{
+		"ID": ns.Namespace.NsId, "Name": ns.Namespace.NsName,
+		"CpuLimit":     types.FormatCPU(float64(ns.Namespace.NsCpuLimit) / types.MilliCPUPerCPU),
+		"MemLimit":     types.FormatSize(float64(ns.Namespace.NsMemLimit) / types.MiBPerGiB),
+		"StorageLimit": types.FormatSize(float64(ns.Namespace.NsStorageLimit) / types.MiBPerGiB),
+		"ClusterID":    ns.Namespace.ClusterId, "ClusterName": ns.Namespace.ClusterName,
+		"Status": ns.NsStatus.String(), "Event": ns.NsEvent, "Apps": len(n.Detail.Apps),
+	}}
+}
+
+// NamespaceResizeInfo is a change of one limit of a namespace, in vCPUs for
+// CPU and GiB for memory and storage.
+type NamespaceResizeInfo struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Cluster     string   `json:"cluster"`
+	Resource    string   `json:"resource"`
+	Current     float64  `json:"current"`
+	New         float64  `json:"new"`
+	ClusterFree *float64 `json:"cluster_free,omitempty"`
+	Fits        bool     `json:"fits"`
+}
+
+// FormatAmount formats an amount of the resource r changes.
+func (r NamespaceResizeInfo) FormatAmount(v float64) string {
+	if r.Resource == "CPU" {
+		return types.FormatCPU(v)
+	}
+
+	return types.FormatSize(v)
+}
+
+type NamespaceResize struct {
+	Resizes []NamespaceResizeInfo
+}
+
+var _ Displayable = &NamespaceResize{}
+
+func (n *NamespaceResize) JSON(out io.Writer) error {
+	return writeJSON(n.Resizes, out)
+}
+
+func (n *NamespaceResize) Cols() []string {
+	return []string{
+		"ID", "Name", "Resource", "Change", "ClusterFree", "Fits",
+	}
+}
+
+func (n *NamespaceResize) ColMap() map[string]string {
+	return map[string]string{
+		"ID": "ID", "Name": "Name", "Cluster": "Cluster", "Resource": "Resource", "Change": "Change",
+		"Current": "Current", "New": "New", "ClusterFree": "Cluster Free", "Fits": "Fits",
+	}
+}
+
+func (n *NamespaceResize) KV() []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, r := range n.Resizes {
+		free, fits := "unknown", "unknown"
+		if r.ClusterFree != nil {
+			free = r.FormatAmount(*r.ClusterFree)
+			fits = "no"
+			if r.Fits {
+				fits = "yes"
+			}
+		}
+
+		m := map[string]interface{}{
+			"ID": r.ID, "Name": r.Name, "Cluster": r.Cluster, "Resource": r.Resource,
+			"Change":  r.FormatAmount(r.Current) + " → " + r.FormatAmount(r.New),
+			"Current": r.FormatAmount(r.Current), "New": r.FormatAmount(r.New),
+			"ClusterFree": free, "Fits": fits,
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
@@ -15,9 +15,11 @@ package displayers
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"github.com/Ankr-network/ankrctl/types"
+	"gopkg.in/yaml.v2"
 	"io"
 	"reflect"
 	"strings"
@@ -70,6 +72,22 @@ type Displayable interface {
 	JSON(io.Writer) error
 }
 
+// Filterable is a Displayable whose rows can be filtered.
+type Filterable interface {
+	Displayable
+
+	// Filter keeps the items whose rows in KV() keep reports true for.
+	Filter(keep func(i int) bool)
+}
+
+// Wide is a Displayable with extra columns for -o wide.
+type Wide interface {
+	Displayable
+
+	// WideCols are the columns shown with -o wide, Cols() and more.
+	WideCols() []string
+}
+
 type Displayer struct {
 	NS     string
 	Config types.Config
@@ -78,13 +96,22 @@ type Displayer struct {
 }
 
 func (d *Displayer) Display() error {
-	output, err := types.AnkrConfig.GetString(types.NSRoot, "output")
+	sortBy, err := d.Config.GetString(d.NS, types.ArgSortBySlug)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	if output == "" {
-		output = "text"
+	if sortBy != "" {
+		item, err := sortItem(d.Item, sortBy)
+		if err != nil {
+			return err
+		}
+		d.Item = item
+	}
+
+	output := outputType()
+	if i := strings.Index(output, "="); i > 0 {
+		return displayTemplate(d.Item, d.Out, output[:i], output[i+1:])
 	}
 
 	switch output {
@@ -97,9 +124,44 @@ func (d *Displayer) Display() error {
 		}
 
 		return displayText(d.Item, d.Out, cols)
+	case "wide":
+		cols, err := handleColumns(d.NS, d.Config)
+		if err != nil {
+			return err
+		}
+
+		if w, ok := d.Item.(Wide); ok && len(cols) == 0 {
+			cols = w.WideCols()
+		}
+		return displayText(d.Item, d.Out, cols)
+	case "yaml":
+		return writeYAML(d.Item, d.Out)
+	case "go-template", "go-template-file", "jsonpath":
+		return displayTemplate(d.Item, d.Out, output, "")
+	case "csv", "tsv":
+		cols, err := handleColumns(d.NS, d.Config)
+		if err != nil {
+			return err
+		}
+
+		w := csv.NewWriter(d.Out)
+		if output == "tsv" {
+			w.Comma = '\t'
+		}
+		return displayCSV(d.Item, w, cols)
 	default:
-		return fmt.Errorf("unknown output type")
+		return fmt.Errorf("unknown output type %q, use text, wide, json, yaml, csv, tsv, go-template=, go-template-file= or jsonpath=", output)
+	}
+}
+
+// outputType returns the output type chosen with --output.
+func outputType() string {
+	output, err := types.AnkrConfig.GetString(types.NSRoot, "output")
+	if err != nil || output == "" {
+		return "text"
 	}
+
+	return output
 }
 
 func writeJSON(item interface{}, w io.Writer) error {
@@ -118,6 +180,69 @@ func writeJSON(item interface{}, w io.Writer) error {
 	return err
 }
 
+// writeYAML writes the same document as the JSON of item, as YAML.
+func writeYAML(item Displayable, w io.Writer) error {
+	var buf bytes.Buffer
+	if err := item.JSON(&buf); err != nil {
+		return err
+	}
+
+	var doc interface{}
+	if err := yaml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		return err
+	}
+
+	b, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+func displayCSV(item Displayable, w *csv.Writer, includeCols []string) error {
+	cols := item.Cols()
+	if len(includeCols) > 0 && includeCols[0] != "" {
+		cols = includeCols
+	}
+
+	if !hc.hideHeader {
+		headers := []string{}
+		for _, k := range cols {
+			col := item.ColMap()[k]
+			if col == "" {
+				return fmt.Errorf("unknown column %q", k)
+			}
+
+			headers = append(headers, col)
+		}
+		if err := w.Write(headers); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range item.KV() {
+		values := []string{}
+		for _, col := range cols {
+			values = append(values, fmt.Sprintf("%v", r[col]))
+		}
+		if err := w.Write(values); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// WriteTable writes item as a text table of cols, or of its default columns,
+// whatever the output format. Commands use it for the sections following
+// their main table.
+func WriteTable(item Displayable, out io.Writer, cols ...string) error {
+	return displayText(item, out, cols)
+}
+
 func displayText(item Displayable, out io.Writer, includeCols []string) error {
 	w := newTabWriter(out)
 
@@ -0,0 +1,106 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Ankr-network/ankrctl/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisplayOutputs(t *testing.T) {
+	key := func() *Key {
+		return &Key{Keystores: []*KeyStore{
+			{Name: "web, prod", Address: "0x1", PublicKey: `say "hi"`},
+			{Name: "db", Address: "0x2", PublicKey: "tab\there"},
+		}}
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		flags  testConfig
+		want   string
+	}{
+		{
+			name:   "csv quotes separators and quotes",
+			output: "csv",
+			flags:  testConfig{},
+			want: "Name,Address,Public Key\n" +
+				"\"web, prod\",0x1,\"say \"\"hi\"\"\"\n" +
+				"db,0x2,tab\there\n",
+		},
+		{
+			name:   "tsv separates with tabs",
+			output: "tsv",
+			flags:  testConfig{},
+			want: "Name\tAddress\tPublic Key\n" +
+				"web, prod\t0x1\t\"say \"\"hi\"\"\"\n" +
+				"db\t0x2\t\"tab\there\"\n",
+		},
+		{
+			name:   "csv with --format",
+			output: "csv",
+			flags:  testConfig{types.ArgFormat: "Address, Name"},
+			want: "Address,Name\n" +
+				"0x1,\"web, prod\"\n" +
+				"0x2,db\n",
+		},
+		{
+			name:   "csv with --no-header",
+			output: "csv",
+			flags:  testConfig{types.ArgFormat: "Name", types.ArgNoHeader: true},
+			want: "\"web, prod\"\n" +
+				"db\n",
+		},
+		{
+			name:   "yaml",
+			output: "yaml",
+			flags:  testConfig{},
+			want: "- address: \"0x1\"\n" +
+				"  name: web, prod\n" +
+				"  publickey: say \"hi\"\n" +
+				"- address: \"0x2\"\n" +
+				"  name: db\n" +
+				"  publickey: \"tab\\there\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			d := &Displayer{Config: tt.flags, Item: key(), Out: &out}
+
+			withOutput(tt.output, func() {
+				assert.NoError(t, d.Display())
+			})
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+
+	// the header is shown again once --no-header is gone
+	withOutput("csv", func() {
+		var out bytes.Buffer
+		d := &Displayer{Config: testConfig{types.ArgFormat: "Name"}, Item: key(), Out: &out}
+		assert.NoError(t, d.Display())
+		assert.Equal(t, "Name\n\"web, prod\"\ndb\n", out.String())
+	})
+
+	withOutput("csv", func() {
+		d := &Displayer{Config: testConfig{types.ArgFormat: "Name,Status"}, Item: key(), Out: &bytes.Buffer{}}
+		assert.EqualError(t, d.Display(), `unknown column "Status"`)
+	})
+}
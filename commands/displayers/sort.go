@@ -0,0 +1,178 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ankr-network/ankrctl/types"
+)
+
+// Sortable is a Displayable whose items can be reordered.
+type Sortable interface {
+	Displayable
+
+	// Reorder puts the items in the order of the KV() row indexes in order.
+	Reorder(order []int)
+}
+
+// sortItem sorts the rows of item by a column, or in descending order when
+// by starts with -. Columns holding numbers, sizes such as 2.00GiB, CPUs
+// such as 0.5 vCPU(s), and dates sort by value.
+func sortItem(item Displayable, by string) (Displayable, error) {
+	s, ok := item.(Sortable)
+	if !ok {
+		return nil, fmt.Errorf("--%s is not supported by this command", types.ArgSortBySlug)
+	}
+
+	desc := strings.HasPrefix(by, "-")
+	col := sortColumn(item, strings.TrimPrefix(by, "-"))
+	if col == "" {
+		cols := []string{}
+		for k := range item.ColMap() {
+			cols = append(cols, k)
+		}
+		sort.Strings(cols)
+		return nil, fmt.Errorf("unknown sort column %q, possible values: %s", by, strings.Join(cols, ","))
+	}
+
+	rows := item.KV()
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := rows[order[i]][col], rows[order[j]][col]
+		if desc {
+			return compareValues(b, a) < 0
+		}
+		return compareValues(a, b) < 0
+	})
+
+	s.Reorder(order)
+	return s, nil
+}
+
+// sortColumn returns the column of item named by name, which is matched
+// case insensitively against both column names and headers.
+func sortColumn(item Displayable, name string) string {
+	for k, header := range item.ColMap() {
+		if strings.EqualFold(k, name) || strings.EqualFold(header, name) {
+			return k
+		}
+	}
+
+	return ""
+}
+
+// compareValues compares two values of a column by number, date or text.
+func compareValues(a, b interface{}) int {
+	if x, ok := sortNumber(a); ok {
+		if y, ok := sortNumber(b); ok {
+			return compareFloats(x, y)
+		}
+	}
+
+	sa, sb := fmt.Sprintf("%v", a), fmt.Sprintf("%v", b)
+	if x, ok := sortTime(sa); ok {
+		if y, ok := sortTime(sb); ok {
+			return compareFloats(float64(x.UnixNano()), float64(y.UnixNano()))
+		}
+	}
+
+	return strings.Compare(strings.ToLower(sa), strings.ToLower(sb))
+}
+
+func compareFloats(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+
+	return 0
+}
+
+var quantityRegexp = regexp.MustCompile(`^\s*([-+]?[0-9]*\.?[0-9]+)\s*([a-zA-Z()%]*)\s*$`)
+
+// sortUnits scales the units found in table columns to a common base, bytes
+// for sizes and mCPUs for CPUs.
+var sortUnits = map[string]float64{
+	"": 1, "b": 1, "%": 1,
+	"k": 1 << 10, "kb": 1 << 10, "ki": 1 << 10, "kib": 1 << 10,
+	"mb": 1 << 20, "mi": 1 << 20, "mib": 1 << 20,
+	"g": 1 << 30, "gb": 1 << 30, "gi": 1 << 30, "gib": 1 << 30,
+	"t": 1 << 40, "tb": 1 << 40, "ti": 1 << 40, "tib": 1 << 40,
+	"mcpu": 1, "mcpus": 1, "mcpu(s)": 1,
+	"vcpu": 1000, "vcpus": 1000, "vcpu(s)": 1000, "cpu": 1000, "cpus": 1000,
+}
+
+func sortNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		m := quantityRegexp.FindStringSubmatch(n)
+		if m == nil {
+			return 0, false
+		}
+
+		unit, ok := sortUnits[strings.ToLower(m[2])]
+		if !ok {
+			return 0, false
+		}
+
+		f, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return 0, false
+		}
+
+		return f * unit, true
+	}
+
+	return 0, false
+}
+
+var sortTimeLayouts = []string{
+	time.RFC822, time.RFC822Z, time.RFC1123, time.RFC1123Z, time.RFC3339, "2006-01-02 15:04:05", "2006-01-02",
+}
+
+func sortTime(s string) (time.Time, bool) {
+	for _, layout := range sortTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
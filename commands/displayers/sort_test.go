@@ -0,0 +1,70 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		a, b interface{}
+		want int
+	}{
+		{a: "0.5 vCPU(s)", b: "2 vCPU(s)", want: -1},
+		{a: "10 vCPU(s)", b: "2 vCPU(s)", want: 1},
+		{a: "1.50GiB", b: "512 MB", want: 1},
+		{a: "2 GB", b: "2GiB", want: 0},
+		{a: "500m", b: "600", want: -1},
+		{a: 10, b: 9, want: 1},
+		{a: "10 May 19 14:55 PDT", b: "02 Jun 19 09:00 PDT", want: -1},
+		{a: "web", b: "Api", want: 1},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, compareValues(tt.a, tt.b), "%v %v", tt.a, tt.b)
+	}
+}
+
+func TestSortItem(t *testing.T) {
+	key := &Key{Keystores: []*KeyStore{
+		{Name: "b", Address: "3"},
+		{Name: "c", Address: "1"},
+		{Name: "a", Address: "2"},
+	}}
+
+	names := func() []string {
+		var out []string
+		for _, k := range key.Keystores {
+			out = append(out, k.Name)
+		}
+		return out
+	}
+
+	_, err := sortItem(key, "name")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, names(), "sort by name")
+
+	_, err = sortItem(key, "-Address")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "a", "c"}, names(), "sort by -Address")
+
+	_, err = sortItem(key, "Status")
+	assert.Error(t, err, "sortItem() with an unknown column should fail")
+
+	_, err = sortItem(&BatchResult{}, "Name")
+	assert.Error(t, err, "sortItem() of a displayer that is not Sortable should fail")
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateFuncs are the helpers available to -o go-template.
+var templateFuncs = template.FuncMap{
+	"json":       templateJSON,
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"unixTime":   unixTime,
+	"date":       formatDate,
+	"ago":        ago,
+	"cores":      cores,
+	"gib":        gib,
+}
+
+// templateData decodes the JSON of item, so templates see the same fields,
+// named by their JSON tags, as -o json prints.
+func templateData(item Displayable) (interface{}, error) {
+	var buf bytes.Buffer
+	if err := item.JSON(&buf); err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(&buf)
+	dec.UseNumber()
+
+	var data interface{}
+	if err := dec.Decode(&data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// displayTemplate evaluates a go-template, go-template-file or jsonpath
+// output against item.
+func displayTemplate(item Displayable, out io.Writer, kind, arg string) error {
+	if arg == "" {
+		return fmt.Errorf("%s output needs a template, such as -o %s=...", kind, kind)
+	}
+
+	data, err := templateData(item)
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case "go-template-file":
+		b, err := ioutil.ReadFile(arg)
+		if err != nil {
+			return err
+		}
+		arg = string(b)
+		fallthrough
+	case "go-template":
+		tmpl, err := template.New("output").Funcs(templateFuncs).Parse(arg)
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(out, data)
+	case "jsonpath":
+		jp, err := parseJSONPath(arg)
+		if err != nil {
+			return err
+		}
+		return jp.Execute(out, data)
+	}
+
+	return fmt.Errorf("unknown output type %q", kind)
+}
+
+func templateJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// unixTime converts a timestamp, given as seconds since the epoch or as a
+// protobuf Timestamp with a seconds field, to a time.
+func unixTime(v interface{}) (time.Time, error) {
+	if m, ok := v.(map[string]interface{}); ok {
+		v = m["seconds"]
+		if v == nil {
+			return time.Unix(0, 0), nil
+		}
+	}
+
+	secs, err := strconv.ParseInt(fmt.Sprint(v), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%v is not a timestamp", v)
+	}
+
+	return time.Unix(secs, 0), nil
+}
+
+// formatDate formats a timestamp with a Go time layout, such as
+// {{ .creation_date | date "2006-01-02" }}.
+func formatDate(layout string, v interface{}) (string, error) {
+	t, err := unixTime(v)
+	if err != nil {
+		return "", err
+	}
+
+	return t.Format(layout), nil
+}
+
+// ago returns how long ago a timestamp was, rounded to the second.
+func ago(v interface{}) (string, error) {
+	t, err := unixTime(v)
+	if err != nil {
+		return "", err
+	}
+
+	return time.Since(t).Round(time.Second).String(), nil
+}
+
+func toFloat(v interface{}) (float64, error) {
+	f, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%v is not a number", v)
+	}
+
+	return f, nil
+}
+
+// cores converts mCPUs, as the hub reports CPU, to vCPUs.
+func cores(v interface{}) (float64, error) {
+	f, err := toFloat(v)
+	return f / 1000, err
+}
+
+// gib converts MiB, as the hub reports memory and storage, to GiB.
+func gib(v interface{}) (float64, error) {
+	f, err := toFloat(v)
+	return f / 1024, err
+}
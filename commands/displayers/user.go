@@ -0,0 +1,146 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"io"
+	"sort"
+	"time"
+)
+
+type WhoAmI struct {
+	Identity   WhoAmIInfo
+	ShowExpiry bool
+}
+
+type WhoAmIInfo struct {
+	Context   string     `json:"context"`
+	ID        string     `json:"id,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	HubURL    string     `json:"hub_url,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+var _ Displayable = &WhoAmI{}
+
+func (w *WhoAmI) JSON(out io.Writer) error {
+	return writeJSON(w.Identity, out)
+}
+
+func (w *WhoAmI) Cols() []string {
+	cols := []string{
+		"Context", "ID", "Email", "HubURL",
+	}
+	if w.ShowExpiry {
+		cols = append(cols, "ExpiresAt", "ExpiresIn")
+	}
+	return cols
+}
+
+func (w *WhoAmI) ColMap() map[string]string {
+	return map[string]string{
+		"Context": "Context", "ID": "ID", "Email": "Email", "HubURL": "Hub URL",
+		"ExpiresAt": "Expires At", "ExpiresIn": "Expires In",
+	}
+}
+
+func (w *WhoAmI) KV() []map[string]interface{} {
+	m := map[string]interface{}{
+		"Context": w.Identity.Context, "ID": w.Identity.ID,
+		"Email": w.Identity.Email, "HubURL": w.Identity.HubURL,
+		"ExpiresAt": "unknown", "ExpiresIn": "unknown",
+	}
+
+	if exp := w.Identity.ExpiresAt; exp != nil {
+		m["ExpiresAt"] = exp.Format(time.RFC822)
+		if left := time.Until(*exp).Round(time.Second); left > 0 {
+			m["ExpiresIn"] = left.String()
+		} else {
+			m["ExpiresIn"] = "expired"
+		}
+	}
+
+	return []map[string]interface{}{m}
+}
+
+type UserDetail struct {
+	User UserInfo
+}
+
+type UserInfo struct {
+	Name             string            `json:"name"`
+	Email            string            `json:"email"`
+	Status           string            `json:"status"`
+	CreationDate     time.Time         `json:"creation_date"`
+	LastModifiedDate time.Time         `json:"last_modified_date"`
+	PubKey           string            `json:"pub_key,omitempty"`
+	ExtraFields      map[string]string `json:"extra_fields,omitempty"`
+}
+
+var _ Displayable = &UserDetail{}
+
+func (u *UserDetail) JSON(out io.Writer) error {
+	return writeJSON(u.User, out)
+}
+
+func (u *UserDetail) Cols() []string {
+	cols := []string{
+		"Name", "Email", "Status", "CreationDate", "LastModifiedDate", "PubKey",
+	}
+	cols = append(cols, u.extraKeys()...)
+	return cols
+}
+
+func (u *UserDetail) ColMap() map[string]string {
+	m := map[string]string{
+		"Name": "Name", "Email": "Email", "Status": "Status", "CreationDate": "Creation Date",
+		"LastModifiedDate": "Last Modified Date", "PubKey": "Pubkey",
+	}
+	for _, k := range u.extraKeys() {
+		m[k] = k
+	}
+	return m
+}
+
+func (u *UserDetail) KV() []map[string]interface{} {
+	m := map[string]interface{}{
+		"Name": u.User.Name, "Email": u.User.Email, "Status": u.User.Status,
+		"CreationDate":     u.User.CreationDate.Format(time.RFC822),
+		"LastModifiedDate": u.User.LastModifiedDate.Format(time.RFC822),
+		"PubKey":           u.User.PubKey,
+	}
+	for k, v := range u.User.ExtraFields {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
+
+	return []map[string]interface{}{m}
+}
+
+// extraKeys returns the names of the extra fields that do not clash with a
+// standard column, sorted.
+func (u *UserDetail) extraKeys() []string {
+	keys := []string{}
+	for k := range u.User.ExtraFields {
+		switch k {
+		case "Name", "Email", "Status", "CreationDate", "LastModifiedDate", "PubKey":
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"io"
+)
+
+type Balance struct {
+	Balances []BalanceInfo
+}
+
+type BalanceInfo struct {
+	Address string `json:"address"`
+	Symbol  string `json:"symbol"`
+	Amount  string `json:"amount"`
+}
+
+var _ Displayable = &Balance{}
+
+func (b *Balance) JSON(out io.Writer) error {
+	return writeJSON(b.Balances, out)
+}
+
+func (b *Balance) Cols() []string {
+	cols := []string{
+		"Address", "Symbol", "Amount",
+	}
+	return cols
+}
+
+func (b *Balance) ColMap() map[string]string {
+	return map[string]string{
+		"Address": "Address", "Symbol": "Symbol", "Amount": "Amount",
+	}
+}
+
+func (b *Balance) KV() []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, b := range b.Balances {
+		m := map[string]interface{}{
+			"Address": b.Address, "Symbol": b.Symbol, "Amount": b.Amount,
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
+
+type Deposit struct {
+	Deposits []DepositInfo
+}
+
+type DepositInfo struct {
+	Time                   string `json:"time"`
+	TxHash                 string `json:"tx_hash"`
+	TxState                string `json:"tx_state"`
+	ConfirmedBlockHeight   string `json:"confirmed_block_height"`
+	FromAccountAddressType string `json:"from_account_address_type"`
+	FromAccountAddress     string `json:"from_account_address"`
+	ToAccountAddressType   string `json:"to_account_address_type"`
+	ToAccountAddress       string `json:"to_account_address"`
+	Amount                 string `json:"amount"`
+}
+
+var _ Displayable = &Deposit{}
+
+func (d *Deposit) JSON(out io.Writer) error {
+	return writeJSON(d.Deposits, out)
+}
+
+func (d *Deposit) Cols() []string {
+	cols := []string{
+		"Time", "Hash", "State", "BlockHeight", "FromType", "From", "ToType", "To", "Amount",
+	}
+	return cols
+}
+
+func (d *Deposit) ColMap() map[string]string {
+	return map[string]string{
+		"Time": "Time", "Hash": "Hash", "State": "State", "BlockHeight": "Confirmed Block Height",
+		"FromType": "From Address Type", "From": "From Address", "ToType": "To Address Type",
+		"To": "To Address", "Amount": "Amount",
+	}
+}
+
+func (d *Deposit) KV() []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, d := range d.Deposits {
+		m := map[string]interface{}{
+			"Time": d.Time, "Hash": d.TxHash, "State": d.TxState, "BlockHeight": d.ConfirmedBlockHeight,
+			"FromType": d.FromAccountAddressType, "From": d.FromAccountAddress,
+			"ToType": d.ToAccountAddressType, "To": d.ToAccountAddress, "Amount": d.Amount,
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
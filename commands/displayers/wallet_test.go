@@ -0,0 +1,49 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalance(t *testing.T) {
+	item := &Balance{Balances: []BalanceInfo{{Address: "0xabc", Symbol: "ANKR", Amount: "1.5"}}}
+	checkColumns(t, item)
+
+	var out bytes.Buffer
+	assert.NoError(t, WriteTable(item, &out))
+	assert.Equal(t, "Address    Symbol    Amount\n"+
+		"0xabc      ANKR      1.5\n", out.String())
+}
+
+func TestDeposit(t *testing.T) {
+	item := &Deposit{Deposits: []DepositInfo{{
+		Time: "2019-06-02T15:00:00Z", TxHash: "0xabc", TxState: "confirmed", ConfirmedBlockHeight: "42",
+		FromAccountAddressType: "ERC20", FromAccountAddress: "0x1",
+		ToAccountAddressType: "MAINNET", ToAccountAddress: "0x2", Amount: "1.5",
+	}}}
+	checkColumns(t, item)
+
+	var out bytes.Buffer
+	assert.NoError(t, WriteTable(item, &out, "Hash", "State", "BlockHeight", "From", "To", "Amount"))
+	assert.Equal(t, "Hash     State        Confirmed Block Height    From Address    To Address    Amount\n"+
+		"0xabc    confirmed    42                        0x1             0x2           1.5\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, item.JSON(&out))
+	assert.Contains(t, out.String(), `"tx_hash": "0xabc"`)
+}
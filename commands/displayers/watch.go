@@ -0,0 +1,155 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	watchAdded    = "ADDED"
+	watchModified = "MODIFIED"
+	watchDeleted  = "DELETED"
+)
+
+// WatchDisplayer displays successive snapshots of a list. On a terminal
+// every snapshot redraws the whole table in place; otherwise only the rows
+// that were added, changed or deleted since the previous snapshot are
+// written, as JSON lines with -o json.
+type WatchDisplayer struct {
+	Displayer
+	Interval time.Duration
+	TTY      bool
+
+	rows map[string]string
+	keys []string
+	seen bool
+}
+
+type watchEvent struct {
+	Type   string                 `json:"type"`
+	Object map[string]interface{} `json:"object"`
+}
+
+// Update displays the next snapshot.
+func (d *WatchDisplayer) Update(item Displayable) error {
+	d.Item = item
+
+	if d.TTY {
+		fmt.Fprint(d.Out, "\033[H\033[2J")
+		fmt.Fprintf(d.Out, "Every %s: %s\n\n", d.Interval, time.Now().Format(time.RFC1123))
+		return d.Display()
+	}
+
+	if outputType() == "json" {
+		return d.updateJSON(item)
+	}
+
+	return d.updateText(item)
+}
+
+func (d *WatchDisplayer) updateJSON(item Displayable) error {
+	enc := json.NewEncoder(d.Out)
+
+	return d.changes(item, func(typ string, r map[string]interface{}) error {
+		return enc.Encode(watchEvent{Type: typ, Object: r})
+	})
+}
+
+func (d *WatchDisplayer) updateText(item Displayable) error {
+	cols, err := handleColumns(d.NS, d.Config)
+	if err != nil {
+		return err
+	}
+	if len(cols) == 0 {
+		cols = item.Cols()
+	}
+
+	w := newTabWriter(d.Out)
+	if !d.seen && !hc.hideHeader {
+		headers := []string{"Change"}
+		for _, k := range cols {
+			col := item.ColMap()[k]
+			if col == "" {
+				return fmt.Errorf("unknown column %q", k)
+			}
+			headers = append(headers, col)
+		}
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+	}
+
+	err = d.changes(item, func(typ string, r map[string]interface{}) error {
+		values := []string{strings.ToLower(typ)}
+		for _, col := range cols {
+			values = append(values, fmt.Sprintf("%v", r[col]))
+		}
+		_, err := fmt.Fprintln(w, strings.Join(values, "\t"))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// changes calls emit for every row of item that differs from the previous
+// snapshot, keyed by the first column, and for every row that is gone.
+func (d *WatchDisplayer) changes(item Displayable, emit func(typ string, r map[string]interface{}) error) error {
+	key := item.Cols()[0]
+	rows := map[string]string{}
+	keys := []string{}
+
+	for _, r := range item.KV() {
+		k := fmt.Sprintf("%v", r[key])
+		b, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+
+		rows[k] = string(b)
+		keys = append(keys, k)
+
+		old, ok := d.rows[k]
+		switch {
+		case !ok:
+			err = emit(watchAdded, r)
+		case old != string(b):
+			err = emit(watchModified, r)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, k := range d.keys {
+		if _, ok := rows[k]; ok {
+			continue
+		}
+
+		var r map[string]interface{}
+		if err := json.Unmarshal([]byte(d.rows[k]), &r); err != nil {
+			return err
+		}
+		if err := emit(watchDeleted, r); err != nil {
+			return err
+		}
+	}
+
+	d.rows, d.keys, d.seen = rows, keys, true
+	return nil
+}
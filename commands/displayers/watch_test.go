@@ -0,0 +1,129 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package displayers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Ankr-network/ankrctl/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// testConfig is a types.Config holding the flags of a test command.
+type testConfig map[string]interface{}
+
+var _ types.Config = testConfig{}
+
+func (c testConfig) Set(ns, key string, val interface{}) { c[key] = val }
+
+func (c testConfig) IsSet(key string) bool {
+	_, ok := c[key]
+	return ok
+}
+
+func (c testConfig) GetString(ns, key string) (string, error) {
+	s, _ := c[key].(string)
+	return s, nil
+}
+
+func (c testConfig) GetBool(ns, key string) (bool, error) {
+	b, _ := c[key].(bool)
+	return b, nil
+}
+
+func (c testConfig) GetInt(ns, key string) (int, error) {
+	i, _ := c[key].(int)
+	return i, nil
+}
+
+func (c testConfig) GetStringSlice(ns, key string) ([]string, error) {
+	s, _ := c[key].([]string)
+	return s, nil
+}
+
+func (c testConfig) GetStringArray(ns, key string) ([]string, error) {
+	s, _ := c[key].([]string)
+	return s, nil
+}
+
+// withOutput runs fn with -o set to output.
+func withOutput(output string, fn func()) {
+	config := types.AnkrConfig
+	defer func() { types.AnkrConfig = config }()
+	types.AnkrConfig = testConfig{"output": output}
+
+	fn()
+}
+
+func watchSnapshots() (*Key, *Key) {
+	first := &Key{Keystores: []*KeyStore{
+		{Name: "a", Address: "1"},
+		{Name: "b", Address: "2"},
+	}}
+	second := &Key{Keystores: []*KeyStore{
+		{Name: "a", Address: "3"},
+		{Name: "c", Address: "4"},
+	}}
+
+	return first, second
+}
+
+func TestWatchDisplayerText(t *testing.T) {
+	var out bytes.Buffer
+	d := &WatchDisplayer{Displayer: Displayer{Config: testConfig{types.ArgFormat: "Name,Address"}, Out: &out}}
+	first, second := watchSnapshots()
+
+	withOutput("text", func() {
+		assert.NoError(t, d.Update(first))
+		assert.Equal(t, "Change    Name    Address\n"+
+			"added     a       1\n"+
+			"added     b       2\n", out.String(), "first snapshot")
+
+		out.Reset()
+		assert.NoError(t, d.Update(second))
+		assert.Equal(t, "modified    a    3\n"+
+			"added       c    4\n"+
+			"deleted     b    2\n", out.String(), "second snapshot")
+
+		out.Reset()
+		assert.NoError(t, d.Update(second))
+		assert.Empty(t, out.String(), "unchanged snapshot")
+	})
+}
+
+func TestWatchDisplayerJSON(t *testing.T) {
+	var out bytes.Buffer
+	d := &WatchDisplayer{Displayer: Displayer{Config: testConfig{}, Out: &out}}
+	first, second := watchSnapshots()
+
+	withOutput("json", func() {
+		assert.NoError(t, d.Update(first))
+		out.Reset()
+		assert.NoError(t, d.Update(second))
+	})
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var e watchEvent
+		if !assert.NoError(t, json.Unmarshal([]byte(line), &e), line) {
+			return
+		}
+		got = append(got, e.Type+" "+e.Object["Name"].(string)+" "+e.Object["Address"].(string))
+	}
+
+	assert.Equal(t, []string{"MODIFIED a 3", "ADDED c 4", "DELETED b 2"}, got)
+}
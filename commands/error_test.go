@@ -44,3 +44,19 @@ func Test_checkErr(t *testing.T) {
 	re := regexp.MustCompile(`an error`)
 	assert.True(t, re.Match(b.Bytes()))
 }
+
+func Test_checkErr_exitError(t *testing.T) {
+	defer func(a func(int)) { exitAction = a }(exitAction)
+	defer func(a func()) { errAction = a }(errAction)
+
+	code := 0
+	exitAction = func(c int) {
+		code = c
+	}
+	errAction = func() {
+		assert.Fail(t, "errAction should not be called for an exitError")
+	}
+
+	checkErr(&exitError{code: exitDrift})
+	assert.Equal(t, exitDrift, code)
+}
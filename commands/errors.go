@@ -33,8 +33,33 @@ var (
 	errAction = func() {
 		os.Exit(1)
 	}
+
+	// exitAction specifies what should happen when a command ends with an
+	// exitError
+	exitAction = func(code int) {
+		os.Exit(code)
+	}
+)
+
+// Exit codes other than 1 that commands end with.
+const (
+	// exitDrift means 'app diff' found the live state differs from the manifest.
+	exitDrift = 2
+
+	// exitPartialFailure means some items of a multi-argument command failed.
+	exitPartialFailure = 3
 )
 
+// exitError ends a command with a specific exit code. The command has
+// already reported the outcome, so nothing more is printed.
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
 func init() {
 	color.Output = ansicolor.NewAnsiColorWriter(os.Stderr)
 }
@@ -52,6 +77,11 @@ func checkErr(err error, cmd ...*cobra.Command) {
 		return
 	}
 
+	if e, ok := err.(*exitError); ok {
+		exitAction(e.code)
+		return
+	}
+
 	output := viper.GetString("output")
 
 	switch output {
@@ -0,0 +1,211 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Ankr-network/ankrctl/commands/displayers"
+	"github.com/Ankr-network/ankrctl/types"
+	"github.com/gobwas/glob"
+)
+
+// filterFlags adds --field-selector to a list command.
+func filterFlags(cmd *Command) {
+	AddStringFlag(cmd, types.ArgFieldSelectorSlug, "", "",
+		"Only list rows whose fields match, such as status=running,cluster!=xyz")
+}
+
+// listFilter selects the rows of a list by name and by field.
+type listFilter struct {
+	globs     []glob.Glob
+	selectors []fieldSelector
+}
+
+// fieldSelector matches a field of a row against a value, such as
+// status=running or cluster!=xyz.
+type fieldSelector struct {
+	field  string
+	value  string
+	negate bool
+}
+
+// newListFilter builds the filter of a list command from its GLOB arguments
+// and --field-selector.
+func newListFilter(c *CmdConfig) (*listFilter, error) {
+	f := &listFilter{}
+	for _, globStr := range c.Args {
+		g, err := glob.Compile(globStr)
+		if err != nil {
+			return nil, fmt.Errorf("unknown glob %q", globStr)
+		}
+
+		f.globs = append(f.globs, g)
+	}
+
+	sel, err := c.Ankr.GetString(c.NS, types.ArgFieldSelectorSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	f.selectors, err = parseFieldSelectors(sel)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// parseFieldSelectors parses a comma separated list of field=value,
+// field==value and field!=value selectors.
+func parseFieldSelectors(s string) ([]fieldSelector, error) {
+	var selectors []fieldSelector
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		var sel fieldSelector
+		if i := strings.Index(part, "!="); i >= 0 {
+			sel = fieldSelector{field: part[:i], value: part[i+2:], negate: true}
+		} else if i := strings.Index(part, "=="); i >= 0 {
+			sel = fieldSelector{field: part[:i], value: part[i+2:]}
+		} else if i := strings.Index(part, "="); i >= 0 {
+			sel = fieldSelector{field: part[:i], value: part[i+1:]}
+		} else {
+			return nil, fmt.Errorf("invalid field selector %q, use field=value or field!=value", part)
+		}
+		sel.field, sel.value = strings.TrimSpace(sel.field), strings.TrimSpace(sel.value)
+
+		if sel.field == "" {
+			return nil, fmt.Errorf("invalid field selector %q, the field is missing", part)
+		}
+		selectors = append(selectors, sel)
+	}
+
+	return selectors, nil
+}
+
+// apply removes the rows of item that do not match. Items that cannot be
+// filtered are returned as they are.
+func (f *listFilter) apply(item displayers.Displayable) (displayers.Displayable, error) {
+	fi, ok := item.(displayers.Filterable)
+	if !ok || (len(f.globs) == 0 && len(f.selectors) == 0) {
+		return item, nil
+	}
+
+	fields := filterFields(item)
+	for _, sel := range f.selectors {
+		if fieldKey(fields, sel.field) == "" {
+			return nil, fmt.Errorf("unknown field %q, possible values: %s", sel.field, strings.Join(fields, ","))
+		}
+	}
+
+	rows := item.KV()
+	fi.Filter(func(i int) bool {
+		return f.match(fields, rows[i])
+	})
+
+	return fi, nil
+}
+
+// prefilter removes the items of item left out by the globs and by the
+// selectors on its own fields, ignoring the other selectors. List commands
+// use it to drop items before fetching the details that apply checks.
+func (f *listFilter) prefilter(item displayers.Filterable) {
+	fields := filterFields(item)
+	known := &listFilter{globs: f.globs}
+	for _, sel := range f.selectors {
+		if fieldKey(fields, sel.field) != "" {
+			known.selectors = append(known.selectors, sel)
+		}
+	}
+	if len(known.globs) == 0 && len(known.selectors) == 0 {
+		return
+	}
+
+	rows := item.KV()
+	item.Filter(func(i int) bool {
+		return known.match(fields, rows[i])
+	})
+}
+
+// filterFields returns the fields the rows of item can be selected by, every
+// column it has, shown or not, sorted.
+func filterFields(item displayers.Displayable) []string {
+	fields := []string{}
+	for k := range item.ColMap() {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+// match reports whether a row matches every glob and selector of f.
+func (f *listFilter) match(fields []string, row map[string]interface{}) bool {
+	if len(f.globs) > 0 {
+		name := fmt.Sprint(row["Name"])
+		matched := false
+		for _, g := range f.globs {
+			if g.Match(name) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	for _, sel := range f.selectors {
+		v := fmt.Sprint(row[fieldKey(fields, sel.field)])
+		if fieldValueMatch(v, sel.value) == sel.negate {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fieldKey returns the one of fields that field names. Fields are matched case
+// insensitively, ignoring dashes and underscores, so cluster-id names ClusterID.
+func fieldKey(fields []string, field string) string {
+	norm := func(s string) string {
+		return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(s))
+	}
+
+	for _, f := range fields {
+		if norm(f) == norm(field) {
+			return f
+		}
+	}
+
+	return ""
+}
+
+// fieldValueMatch reports whether the value of a field is want. Values are
+// compared case insensitively, and a status such as app_running or
+// NS_RUNNING also matches running.
+func fieldValueMatch(v, want string) bool {
+	if strings.EqualFold(v, want) {
+		return true
+	}
+
+	parts := strings.SplitN(v, "_", 2)
+	return len(parts) == 2 && strings.EqualFold(parts[1], want)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/Ankr-network/ankrctl/commands/displayers"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/gobwas/glob"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFieldSelectors(t *testing.T) {
+	got, err := parseFieldSelectors("status=running, cluster!=xyz,name==web")
+	assert.NoError(t, err)
+	assert.Equal(t, []fieldSelector{
+		{field: "status", value: "running"},
+		{field: "cluster", value: "xyz", negate: true},
+		{field: "name", value: "web"},
+	}, got)
+
+	for _, s := range []string{"status", "=running"} {
+		_, err := parseFieldSelectors(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestListFilterApply(t *testing.T) {
+	keys := func() *displayers.Key {
+		return &displayers.Key{Keystores: []*displayers.KeyStore{
+			{Name: "web-1", Address: "a1"},
+			{Name: "web-2", Address: "a2"},
+			{Name: "db-1", Address: "a3"},
+		}}
+	}
+	names := func(item displayers.Displayable) []string {
+		var out []string
+		for _, r := range item.KV() {
+			out = append(out, r["Name"].(string))
+		}
+		return out
+	}
+
+	tests := []struct {
+		name  string
+		globs []string
+		sel   string
+		want  []string
+	}{
+		{name: "no filter", want: []string{"web-1", "web-2", "db-1"}},
+		{name: "glob", globs: []string{"web-*"}, want: []string{"web-1", "web-2"}},
+		{name: "globs", globs: []string{"*-1"}, want: []string{"web-1", "db-1"}},
+		{name: "selector", sel: "address=A2", want: []string{"web-2"}},
+		{name: "negated selector", globs: []string{"web-*"}, sel: "address!=a1", want: []string{"web-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &listFilter{}
+			for _, g := range tt.globs {
+				f.globs = append(f.globs, glob.MustCompile(g))
+			}
+
+			var err error
+			f.selectors, err = parseFieldSelectors(tt.sel)
+			assert.NoError(t, err)
+
+			item, err := f.apply(keys())
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, names(item))
+			}
+		})
+	}
+
+	f := &listFilter{selectors: []fieldSelector{{field: "status", value: "running"}}}
+	_, err := f.apply(keys())
+	assert.EqualError(t, err, `unknown field "status", possible values: Address,Name,PublicKey`)
+}
+
+func TestListFilterApplyHiddenField(t *testing.T) {
+	apps := &displayers.AppReport{Apps: []*common_proto.AppReport{
+		testApp("app-1", "web", "ns-1"),
+		testApp("app-2", "db", "ns-2"),
+	}}
+
+	f := &listFilter{}
+	var err error
+	f.selectors, err = parseFieldSelectors("namespace-id=ns-2")
+	assert.NoError(t, err)
+
+	item, err := f.apply(apps)
+	if assert.NoError(t, err) && assert.Len(t, item.KV(), 1) {
+		assert.Equal(t, "app-2", item.KV()[0]["ID"])
+	}
+}
+
+func TestListFilterPrefilter(t *testing.T) {
+	charts := &displayers.Chart{Charts: []*common_proto.Chart{
+		{ChartRepo: "stable", ChartName: "wordpress"},
+		{ChartRepo: "user", ChartName: "wordpress"},
+		{ChartRepo: "stable", ChartName: "mysql"},
+	}}
+
+	f := &listFilter{globs: []glob.Glob{glob.MustCompile("word*")}}
+	var err error
+	f.selectors, err = parseFieldSelectors("repo=stable,version=1.0.0")
+	assert.NoError(t, err)
+
+	f.prefilter(charts)
+	assert.Equal(t, []*common_proto.Chart{{ChartRepo: "stable", ChartName: "wordpress"}}, charts.Charts)
+}
+
+func TestFieldValueMatch(t *testing.T) {
+	tests := []struct {
+		v, want string
+		match   bool
+	}{
+		{"running", "running", true},
+		{"app_running", "running", true},
+		{"NS_RUNNING", "running", true},
+		{"app_running", "app_running", true},
+		{"app_failed", "running", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, fieldValueMatch(tt.v, tt.want), "%s %s", tt.v, tt.want)
+	}
+}
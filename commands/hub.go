@@ -0,0 +1,268 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Ankr-network/ankrctl/types"
+	ankr_const "github.com/Ankr-network/dccn-common"
+	gwdcmgr "github.com/Ankr-network/dccn-common/protos/gateway/dcmgr/v1"
+	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
+	gwusermgr "github.com/Ankr-network/dccn-common/protos/gateway/usermgr/v1"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var port = ":" + strconv.Itoa(ankr_const.DefaultPort)
+
+var clientURL = "client.dccn.ankr.com"
+
+// defaultHubTimeout is applied to every hub call that has no deadline of its own.
+const defaultHubTimeout = ankr_const.ClientTimeOut * time.Second
+
+// refreshMethod is the hub RPC that renews an access token. It is never
+// refreshed and retried itself.
+const refreshMethod = "RefreshSession"
+
+// publicMethods are the hub RPCs that may be called without an access token.
+var publicMethods = map[string]bool{
+	"Register":            true,
+	"Login":               true,
+	"ConfirmRegistration": true,
+	"ForgotPassword":      true,
+	"ConfirmPassword":     true,
+}
+
+// HubClient is the shared connection to the Ankr DCCN hub. It dials lazily,
+// reuses a single connection for every service client it hands out and
+// attaches the access token and a default timeout to each call.
+type HubClient struct {
+	URL     string
+	Timeout time.Duration
+	Token   func() string
+
+	// Refresh, if set, replaces the access token stale with a new one.
+	Refresh func(ctx context.Context, stale string) error
+
+	// Verbose logs each call and its duration to Log, Trace additionally
+	// logs the request and the response.
+	Verbose bool
+	Trace   bool
+	Log     io.Writer
+
+	mu   sync.Mutex
+	conn *grpc.ClientConn
+}
+
+// NewHubClient creates a HubClient for the hub at url. token is consulted on
+// every call, so a refreshed token is picked up without redialing.
+func NewHubClient(url string, token func() string) *HubClient {
+	return &HubClient{
+		URL:     url,
+		Timeout: defaultHubTimeout,
+		Token:   token,
+		Log:     os.Stderr,
+	}
+}
+
+// AppMgr returns a client for the app, namespace and chart services.
+func (h *HubClient) AppMgr() (gwtaskmgr.AppMgrClient, error) {
+	conn, err := h.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	return gwtaskmgr.NewAppMgrClient(conn), nil
+}
+
+// UserMgr returns a client for the user and account services.
+func (h *HubClient) UserMgr() (gwusermgr.UserMgrClient, error) {
+	conn, err := h.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	return gwusermgr.NewUserMgrClient(conn), nil
+}
+
+// DCAPI returns a client for the data center services.
+func (h *HubClient) DCAPI() (gwdcmgr.DCAPIClient, error) {
+	conn, err := h.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	return gwdcmgr.NewDCAPIClient(conn), nil
+}
+
+// Close closes the underlying connection, if one was opened.
+func (h *HubClient) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.conn == nil {
+		return nil
+	}
+
+	err := h.conn.Close()
+	h.conn = nil
+	return err
+}
+
+func (h *HubClient) dial() (*grpc.ClientConn, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.conn != nil {
+		return h.conn, nil
+	}
+
+	conn, err := grpc.Dial(h.URL, grpc.WithInsecure(), grpc.WithUnaryInterceptor(h.intercept))
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to hub %s: %v", h.URL, err)
+	}
+
+	h.conn = conn
+	return conn, nil
+}
+
+// intercept authenticates and bounds every unary call made on the connection.
+// An expired access token is refreshed before the call, and a call rejected
+// as unauthenticated is retried once with a refreshed token. Public calls are
+// never refreshed and are made without an expired token.
+func (h *HubClient) intercept(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+
+	if _, ok := ctx.Deadline(); !ok && h.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.Timeout)
+		defer cancel()
+	}
+
+	name := path.Base(method)
+	public := publicMethods[name]
+	refreshable := h.Refresh != nil && name != refreshMethod && !public
+
+	token := h.token()
+	if tokenExpired(token, time.Now()) {
+		switch {
+		case public:
+			// an expired session must not keep the user from logging in again
+			token = ""
+		case refreshable:
+			if err := h.Refresh(ctx, token); err != nil {
+				return err
+			}
+			token = h.token()
+		}
+	}
+
+	if token == "" && !public {
+		return ErrNoAccessToken
+	}
+
+	err := h.invoke(ctx, token, method, req, reply, cc, invoker, opts...)
+	if refreshable && token != "" && status.Code(err) == codes.Unauthenticated {
+		if rerr := h.Refresh(ctx, token); rerr != nil {
+			return err
+		}
+
+		return h.invoke(ctx, h.token(), method, req, reply, cc, invoker, opts...)
+	}
+
+	return err
+}
+
+func (h *HubClient) token() string {
+	if h.Token == nil {
+		return ""
+	}
+
+	return h.Token()
+}
+
+// invoke makes a single call with token attached, logging it if asked to.
+func (h *HubClient) invoke(ctx context.Context, token, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+
+	if token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "token", token)
+	}
+
+	if !h.Verbose && !h.Trace {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	if h.Trace {
+		fmt.Fprintf(h.Log, "--> %s %s\n", method, traceJSON(req))
+	}
+
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	switch {
+	case err != nil:
+		fmt.Fprintf(h.Log, "<-- %s %s (%s)\n", method, status.Code(err), elapsed)
+	case h.Trace:
+		fmt.Fprintf(h.Log, "<-- %s OK (%s) %s\n", method, elapsed, traceJSON(reply))
+	default:
+		fmt.Fprintf(h.Log, "<-- %s OK (%s)\n", method, elapsed)
+	}
+
+	return err
+}
+
+func traceJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+
+	return string(b)
+}
+
+// hubURL returns the address of the hub, including the port.
+func hubURL() string {
+	return hubAddr() + port
+}
+
+// hubAddr returns the hub address of the active auth context, unless one
+// was given with --hub-url or ANKR_HUB_URL.
+func hubAddr() string {
+	name := currentContext()
+	if name != defaultContext && !isTransient(types.ArgHubURL) {
+		if url := viper.GetString(contextKey(name, types.ArgHubURL)); url != "" {
+			return url
+		}
+	}
+
+	if HubURL != "" {
+		return HubURL
+	}
+
+	return viper.GetString(types.ArgHubURL)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// testToken returns a JWT expiring at exp.
+func testToken(exp time.Time) string {
+	payload := fmt.Sprintf(`{"exp":%d}`, exp.Unix())
+	return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2ln"
+}
+
+// fakeSession is the token store of a HubClient under test. Refresh replaces
+// the token with fresh, or fails with err.
+type fakeSession struct {
+	token     string
+	fresh     string
+	err       error
+	refreshed int
+}
+
+func (s *fakeSession) Token() string { return s.token }
+
+func (s *fakeSession) Refresh(ctx context.Context, stale string) error {
+	s.refreshed++
+	if s.err != nil {
+		return s.err
+	}
+	s.token = s.fresh
+	return nil
+}
+
+// recordingInvoker records the token of every call and answers with the
+// errors in errs, in order, then with nil.
+type recordingInvoker struct {
+	tokens []string
+	errs   []error
+}
+
+func (r *recordingInvoker) invoke(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	token := ""
+	if v := md.Get("token"); len(v) > 0 {
+		token = v[0]
+	}
+	r.tokens = append(r.tokens, token)
+
+	if len(r.errs) == 0 {
+		return nil
+	}
+	err := r.errs[0]
+	r.errs = r.errs[1:]
+	return err
+}
+
+func TestHubClientIntercept(t *testing.T) {
+	invoked := false
+	invoker := func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		invoked = true
+		_, ok := ctx.Deadline()
+		assert.True(t, ok, "expected a default deadline")
+		return nil
+	}
+
+	h := NewHubClient("localhost:50051", func() string { return "" })
+
+	err := h.intercept(context.Background(), "/gwtaskmgr.AppMgr/AppList", nil, nil, nil, invoker)
+	assert.Equal(t, ErrNoAccessToken, err)
+	assert.False(t, invoked)
+
+	err = h.intercept(context.Background(), "/gwusermgr.UserMgr/Login", nil, nil, nil, invoker)
+	assert.NoError(t, err)
+	assert.True(t, invoked)
+
+	invoked = false
+	h.Token = func() string { return "token" }
+	h.Timeout = time.Minute
+	err = h.intercept(context.Background(), "/gwtaskmgr.AppMgr/AppList", nil, nil, nil, invoker)
+	assert.NoError(t, err)
+	assert.True(t, invoked)
+}
+
+func TestHubClientInterceptRefresh(t *testing.T) {
+	expired := testToken(time.Now().Add(-time.Hour))
+	valid := testToken(time.Now().Add(time.Hour))
+	fresh := testToken(time.Now().Add(2 * time.Hour))
+	unauthenticated := status.Error(codes.Unauthenticated, "token revoked")
+
+	tests := []struct {
+		name          string
+		method        string
+		session       fakeSession
+		errs          []error
+		wantErr       error
+		wantRefreshed int
+		wantTokens    []string
+	}{
+		{
+			name:          "expired token is refreshed before the call",
+			method:        "/gwtaskmgr.AppMgr/AppList",
+			session:       fakeSession{token: expired, fresh: fresh},
+			wantRefreshed: 1,
+			wantTokens:    []string{fresh},
+		},
+		{
+			name:          "failed refresh stops the call",
+			method:        "/gwtaskmgr.AppMgr/AppList",
+			session:       fakeSession{token: expired, err: errors.New("session expired")},
+			wantErr:       errors.New("session expired"),
+			wantRefreshed: 1,
+		},
+		{
+			name:          "unauthenticated call is retried once",
+			method:        "/gwtaskmgr.AppMgr/AppList",
+			session:       fakeSession{token: valid, fresh: fresh},
+			errs:          []error{unauthenticated, unauthenticated},
+			wantErr:       unauthenticated,
+			wantRefreshed: 1,
+			wantTokens:    []string{valid, fresh},
+		},
+		{
+			name:          "unauthenticated call is not retried when the refresh fails",
+			method:        "/gwtaskmgr.AppMgr/AppList",
+			session:       fakeSession{token: valid, err: errors.New("session expired")},
+			errs:          []error{unauthenticated},
+			wantErr:       unauthenticated,
+			wantRefreshed: 1,
+			wantTokens:    []string{valid},
+		},
+		{
+			name:       "public call skips the refresh and the expired token",
+			method:     "/gwusermgr.UserMgr/Login",
+			session:    fakeSession{token: expired, err: errors.New("session expired")},
+			wantTokens: []string{""},
+		},
+		{
+			name:       "unauthenticated public call is not retried",
+			method:     "/gwusermgr.UserMgr/Login",
+			session:    fakeSession{token: valid, fresh: fresh},
+			errs:       []error{unauthenticated},
+			wantErr:    unauthenticated,
+			wantTokens: []string{valid},
+		},
+		{
+			name:       "refresh call is never refreshed",
+			method:     "/gwusermgr.UserMgr/RefreshSession",
+			session:    fakeSession{token: expired, fresh: fresh},
+			wantTokens: []string{expired},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := tt.session
+			h := NewHubClient("localhost:50051", session.Token)
+			h.Refresh = session.Refresh
+
+			inv := &recordingInvoker{errs: tt.errs}
+			err := h.intercept(context.Background(), tt.method, nil, nil, nil, inv.invoke)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantRefreshed, session.refreshed)
+			assert.Equal(t, tt.wantTokens, inv.tokens)
+		})
+	}
+}
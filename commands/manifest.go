@@ -0,0 +1,245 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/Ankr-network/ankrctl/types"
+	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+	"k8s.io/helm/pkg/chartutil"
+)
+
+// userChartRepo is the repo holding the charts uploaded or saved by the user.
+const userChartRepo = "user"
+
+// valuesVersionSep separates the source chart version from the hash of the
+// values in the version of a chart saved with the values of an app.
+const valuesVersionSep = "-values-"
+
+// Manifest describes the apps managed by 'app apply' and 'app diff'.
+type Manifest struct {
+	Apps []AppManifest `yaml:"apps" json:"apps"`
+}
+
+// AppManifest is the desired state of a single app.
+type AppManifest struct {
+	Name      string                 `yaml:"name" json:"name"`
+	Chart     ChartRef               `yaml:"chart" json:"chart"`
+	Namespace NamespaceRef           `yaml:"namespace" json:"namespace"`
+	Values    map[string]interface{} `yaml:"values,omitempty" json:"-"`
+}
+
+// ChartRef points to a chart version in a repo.
+type ChartRef struct {
+	Name    string `yaml:"name" json:"name"`
+	Repo    string `yaml:"repo" json:"repo"`
+	Version string `yaml:"version" json:"version"`
+}
+
+// NamespaceRef selects the namespace of an app by ID or by name. A namespace
+// selected by name that does not exist yet is created with the given limits.
+type NamespaceRef struct {
+	ID           string `yaml:"id,omitempty" json:"id,omitempty"`
+	Name         string `yaml:"name,omitempty" json:"name,omitempty"`
+	ClusterID    string `yaml:"clusterId,omitempty" json:"clusterId,omitempty"`
+	CpuLimit     string `yaml:"cpuLimit,omitempty" json:"cpuLimit,omitempty"`
+	MemLimit     string `yaml:"memLimit,omitempty" json:"memLimit,omitempty"`
+	StorageLimit string `yaml:"storageLimit,omitempty" json:"storageLimit,omitempty"`
+}
+
+// hasLimits reports whether any namespace limit is given.
+func (n NamespaceRef) hasLimits() bool {
+	return n.CpuLimit != "" || n.MemLimit != "" || n.StorageLimit != ""
+}
+
+// target returns the chart the app is deployed from. Apps with values are
+// deployed from a copy of their chart saved to the user repo under the app
+// name, which carries the values. Its version is the chart version followed
+// by a hash of the values, so new values are saved as a new version instead
+// of over the one deployed.
+func (a AppManifest) target() (ChartRef, error) {
+	if len(a.Values) == 0 {
+		return a.Chart, nil
+	}
+
+	b, err := yaml.Marshal(a.Values)
+	if err != nil {
+		return ChartRef{}, fmt.Errorf("unable to encode values of app %s: %v", a.Name, err)
+	}
+	sum := sha256.Sum256(b)
+
+	return ChartRef{
+		Name:    a.Name,
+		Repo:    userChartRepo,
+		Version: fmt.Sprintf("%s%s%x", a.Chart.Version, valuesVersionSep, sum[:4]),
+	}, nil
+}
+
+// sourceVersion returns the version of the chart a chart saved by target
+// was copied from.
+func sourceVersion(version string) string {
+	return strings.SplitN(version, valuesVersionSep, 2)[0]
+}
+
+// valuesVersion returns the part of a version naming the values a chart was
+// saved with by target, empty for a chart without values.
+func valuesVersion(version string) string {
+	return strings.TrimPrefix(version, sourceVersion(version))
+}
+
+// normalizeValues returns values the way they read back from a values.yaml,
+// so values from a manifest and from a chart can be compared.
+func normalizeValues(values map[string]interface{}) (map[string]interface{}, error) {
+	b, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return chartutil.ReadValues(b)
+}
+
+// valuesEqual reports whether the values of a chart match the values of a
+// manifest.
+func valuesEqual(chart, manifest map[string]interface{}) bool {
+	a, err := normalizeValues(chart)
+	if err != nil {
+		return false
+	}
+
+	b, err := normalizeValues(manifest)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// chartValues returns the values.yaml of a chart version.
+func chartValues(ctx context.Context, appClient gwtaskmgr.AppMgrClient, ref ChartRef) (map[string]interface{}, error) {
+	r, err := appClient.ChartDetail(ctx, &gwtaskmgr.ChartDetailRequest{
+		ChartName: ref.Name,
+		ChartRepo: ref.Repo,
+		ChartVer:  ref.Version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+	}
+
+	values, err := chartutil.ReadValues([]byte(fmt.Sprintf("%s", r.ValuesYaml)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse values of %s/%s@%s: %v", ref.Repo, ref.Name, ref.Version, err)
+	}
+
+	return values, nil
+}
+
+// appValues returns the values app runs with, its values merged over the
+// values.yaml of its chart.
+func appValues(ctx context.Context, appClient gwtaskmgr.AppMgrClient, app AppManifest) (map[string]interface{}, error) {
+	values, err := chartValues(ctx, appClient, app.Chart)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeValues(values, app.Values), nil
+}
+
+func (a AppManifest) validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("app name is required")
+	}
+	if a.Chart.Name == "" || a.Chart.Repo == "" || a.Chart.Version == "" {
+		return fmt.Errorf("app %s: chart name, repo and version are required", a.Name)
+	}
+	if a.Namespace.ID == "" && a.Namespace.Name == "" {
+		return fmt.Errorf("app %s: namespace id or name is required", a.Name)
+	}
+
+	return nil
+}
+
+// readManifest reads a yaml or json manifest from file, or from stdin when
+// file is "-".
+func readManifest(file string) (*Manifest, error) {
+	var b []byte
+	var err error
+	if file == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	if err := yaml.UnmarshalStrict(b, m); err != nil {
+		return nil, fmt.Errorf("unable to parse manifest %s: %v", file, err)
+	}
+
+	names := map[string]bool{}
+	for _, app := range m.Apps {
+		if err := app.validate(); err != nil {
+			return nil, err
+		}
+		if names[app.Name] {
+			return nil, fmt.Errorf("app %s is declared more than once", app.Name)
+		}
+		names[app.Name] = true
+	}
+
+	return m, nil
+}
+
+// parseCpuLimit converts a cpu limit, such as 2, 0.5 or 500m, to the mCPUs
+// used by the hub.
+func parseCpuLimit(cpu string) (uint32, error) {
+	v, err := types.ParseCPU(cpu)
+	if err != nil {
+		return 0, fmt.Errorf("Cpu Limit: %v", err)
+	}
+
+	return v, nil
+}
+
+// parseMemLimit converts a memory limit, such as 2, 512Mi or 1.5Gi, to the
+// MiB used by the hub.
+func parseMemLimit(mem string) (uint32, error) {
+	v, err := types.ParseSize(mem)
+	if err != nil {
+		return 0, fmt.Errorf("Mem Limit: %v", err)
+	}
+
+	return v, nil
+}
+
+// parseStorageLimit converts a storage limit, such as 20, 20Gi or 20G, to
+// the MiB used by the hub.
+func parseStorageLimit(storage string) (uint32, error) {
+	v, err := types.ParseSize(storage)
+	if err != nil {
+		return 0, fmt.Errorf("Storage Limit: %v", err)
+	}
+
+	return v, nil
+}
@@ -15,26 +15,16 @@ package commands
 
 import (
 	"fmt"
-	"log"
-	"sync"
-	"time"
-
-	"github.com/spf13/viper"
+	"strings"
 
 	"github.com/Ankr-network/ankrctl/commands/displayers"
-	"github.com/gobwas/glob"
 	"github.com/spf13/cobra"
 
 	"context"
-	"strconv"
 
 	"github.com/Ankr-network/ankrctl/types"
-	ankr_const "github.com/Ankr-network/dccn-common"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
-	gwusermgr "github.com/Ankr-network/dccn-common/protos/gateway/usermgr/v1"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -54,28 +44,39 @@ func namespaceCmd() *Command {
 
 	//DCCN-CLI namespace create
 	cmdRunNamespaceCreate := CmdBuilder(cmd, RunNamespaceCreate, "create <ns-name> [ns-name ...]", "create namespace", Writer,
-		aliasOpt("cr"), docCategories("namespace"))
-	AddStringFlag(cmdRunNamespaceCreate, types.ArgNsCpuLimitSlug, "", "", "Namespace CPU Limit (in vCPUs)", requiredOpt())
-	AddStringFlag(cmdRunNamespaceCreate, types.ArgNsMemLimitSlug, "", "", "Namespace MEM Limit (in GiB)", requiredOpt())
-	AddStringFlag(cmdRunNamespaceCreate, types.ArgNsStorageLimitSlug, "", "", "Namespace Storage Limit (in GiB)", requiredOpt())
-	AddStringFlag(cmdRunNamespaceCreate, types.ArgNsClusterIDSlug, "", "", "Namespace Cluster Id")
+		aliasOpt("cr"), displayerType(&displayers.BatchResult{}), docCategories("namespace"))
+	AddStringFlag(cmdRunNamespaceCreate, types.ArgNsCpuLimitSlug, "", "", "Namespace CPU Limit (in vCPUs, or mCPUs such as 500m)", requiredOpt())
+	AddStringFlag(cmdRunNamespaceCreate, types.ArgNsMemLimitSlug, "", "", "Namespace MEM Limit (in GiB, or with a unit such as 512Mi)", requiredOpt())
+	AddStringFlag(cmdRunNamespaceCreate, types.ArgNsStorageLimitSlug, "", "", "Namespace Storage Limit (in GiB, or with a unit such as 20G)", requiredOpt())
+	AddStringFlag(cmdRunNamespaceCreate, types.ArgNsClusterIDSlug, "", "", "Namespace Cluster Id, or auto for the recommended cluster")
+	batchFlags(cmdRunNamespaceCreate)
 
 	//DCCN-CLI namespace list
 	cmdRunNamespaceList := CmdBuilder(cmd, RunNamespaceList, "list [GLOB]", "list namespace", Writer,
-		aliasOpt("ls"), displayerType(&displayers.Cluster{}), docCategories("namespace"))
-	_ = cmdRunNamespaceList
+		aliasOpt("ls"), displayerType(&displayers.Namespace{}), docCategories("namespace"))
+	filterFlags(cmdRunNamespaceList)
+	watchFlags(cmdRunNamespaceList)
+
+	//DCCN-CLI namespace describe
+	cmdRunNamespaceDescribe := CmdBuilder(cmd, RunNamespaceDescribe, "describe <namespace-id>",
+		"show a namespace with its apps and the headroom of its cluster", Writer, aliasOpt("ds"),
+		displayerType(&displayers.NamespaceDetail{}), docCategories("namespace"))
+	_ = cmdRunNamespaceDescribe
 
 	//DCCN-CLI namespace update
 	cmdRunNamespaceUpdate := CmdBuilder(cmd, RunNamespaceUpdate, "update <namespace-id> [namespace-id ...]", "update namespace", Writer,
-		aliasOpt("ud"), docCategories("namespace"))
-	AddStringFlag(cmdRunNamespaceUpdate, types.ArgNsCpuLimitSlug, "", "", "Namespace CPU Limit (in vCPUs)", requiredOpt())
-	AddStringFlag(cmdRunNamespaceUpdate, types.ArgNsMemLimitSlug, "", "", "Namespace MEM Limit (in GiB)", requiredOpt())
-	AddStringFlag(cmdRunNamespaceUpdate, types.ArgNsStorageLimitSlug, "", "", "Namespace Storage Limit (in GiB)", requiredOpt())
+		aliasOpt("ud"), displayerType(&displayers.NamespaceResize{}), docCategories("namespace"))
+	AddStringFlag(cmdRunNamespaceUpdate, types.ArgNsCpuLimitSlug, "", "", "Namespace CPU Limit (in vCPUs, or mCPUs such as 500m)")
+	AddStringFlag(cmdRunNamespaceUpdate, types.ArgNsMemLimitSlug, "", "", "Namespace MEM Limit (in GiB, or with a unit such as 512Mi)")
+	AddStringFlag(cmdRunNamespaceUpdate, types.ArgNsStorageLimitSlug, "", "", "Namespace Storage Limit (in GiB, or with a unit such as 20G)")
+	AddBoolFlag(cmdRunNamespaceUpdate, types.ArgDryRunSlug, "", false, "Show the new limits and whether they fit the cluster without updating")
+	batchFlags(cmdRunNamespaceUpdate)
 
 	//DCCN-CLI namespace delete
 	cmdRunNamespaceDelete := CmdBuilder(cmd, RunNamespaceDelete, "delete <namespace-id> [namespace-id ...]", "delete namespace",
-		Writer, aliasOpt("dl"), docCategories("namespace"))
+		Writer, aliasOpt("dl"), displayerType(&displayers.BatchResult{}), docCategories("namespace"))
 	AddBoolFlag(cmdRunNamespaceDelete, types.ArgForce, types.ArgShortForce, false, "Force namespace delete")
+	batchFlags(cmdRunNamespaceDelete)
 
 	return cmd
 }
@@ -87,27 +88,7 @@ func RunNamespaceCreate(c *CmdConfig) error {
 		return types.NewMissingArgsErr(c.NS)
 	}
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("did not connect: %v", err)
-	}
-	defer conn.Close()
-
-	appClient := gwtaskmgr.NewAppMgrClient(conn)
+	appClient, err := c.AppMgr()
 	if err != nil {
 		return err
 	}
@@ -117,7 +98,7 @@ func RunNamespaceCreate(c *CmdConfig) error {
 		return err
 	}
 
-	nsCpuLimit, err := strconv.ParseFloat(cpuLimit, 32)
+	nsCpuLimit, err := parseCpuLimit(cpuLimit)
 	if err != nil {
 		return err
 	}
@@ -126,7 +107,7 @@ func RunNamespaceCreate(c *CmdConfig) error {
 	if err != nil {
 		return err
 	}
-	nsMemLimit, err := strconv.ParseFloat(memLimit, 32)
+	nsMemLimit, err := parseMemLimit(memLimit)
 	if err != nil {
 		return err
 	}
@@ -136,7 +117,7 @@ func RunNamespaceCreate(c *CmdConfig) error {
 		return err
 	}
 
-	nsStorageLimit, err := strconv.ParseFloat(storageLimit, 32)
+	nsStorageLimit, err := parseStorageLimit(storageLimit)
 	if err != nil {
 		return err
 	}
@@ -146,185 +127,364 @@ func RunNamespaceCreate(c *CmdConfig) error {
 		return err
 	}
 
+	nsClusterId, err = resolveClusterID(c, nsClusterId, nsCpuLimit, nsMemLimit, nsStorageLimit)
+	if err != nil {
+		return err
+	}
+
 	createNamespaceRequest := &gwtaskmgr.CreateNamespaceRequest{
-		NsCpuLimit:     uint32(nsCpuLimit * 1000),
-		NsMemLimit:     uint32(nsMemLimit * 1024),
-		NsStorageLimit: uint32(nsStorageLimit * 1024),
+		NsCpuLimit:     nsCpuLimit,
+		NsMemLimit:     nsMemLimit,
+		NsStorageLimit: nsStorageLimit,
 		ClusterId:      nsClusterId,
 	}
 
-	var wg sync.WaitGroup
-	errs := make(chan error, len(c.Args))
-	for _, name := range c.Args {
-		createNamespaceRequest.NsName = name
-
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			rsp, err := appClient.CreateNamespace(tokenctx, createNamespaceRequest)
-			if err != nil {
-				errs <- err
-			} else {
-				if rsp != nil {
-					fmt.Printf("Namespace %s create success. \n", rsp.NsId)
-				}
-			}
-		}()
+	b, err := newBatch(c, "create")
+	if err != nil {
+		return err
 	}
-	wg.Wait()
-	close(errs)
 
-	for err := range errs {
+	results := b.run(c.Args, func(name string) (string, error) {
+		rsp, err := appClient.CreateNamespace(context.Background(), &gwtaskmgr.CreateNamespaceRequest{
+			NsName:         name,
+			NsCpuLimit:     createNamespaceRequest.NsCpuLimit,
+			NsMemLimit:     createNamespaceRequest.NsMemLimit,
+			NsStorageLimit: createNamespaceRequest.NsStorageLimit,
+			ClusterId:      createNamespaceRequest.ClusterId,
+		})
 		if err != nil {
-			return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+			return "", err
 		}
-	}
-	return nil
+		return rsp.NsId, nil
+	})
+
+	return c.displayBatch(results)
 }
 
 // RunNamespaceList returns a list of namespace.
 func RunNamespaceList(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
+	appClient, err := c.AppMgr()
+	if err != nil {
+		return err
 	}
 
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	matches := []glob.Glob{}
-	for _, globStr := range c.Args {
-		g, err := glob.Compile(globStr)
+	return c.DisplayList(func() (displayers.Displayable, error) {
+		r, err := appClient.NamespaceList(context.Background(), &common_proto.Empty{})
 		if err != nil {
-			return fmt.Errorf("unknown glob %q", globStr)
+			return nil, fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 		}
 
-		matches = append(matches, g)
+		var nsList []common_proto.NamespaceReport
+		for _, nsReport := range r.NsReports {
+			nsList = append(nsList, *nsReport)
+		}
+
+		return &displayers.Namespace{Namespaces: nsList}, nil
+	})
+}
+
+// RunNamespaceDescribe shows a namespace, by ID or name, with the apps
+// deployed in it and the capacity left on its cluster.
+func RunNamespaceDescribe(c *CmdConfig) error {
+
+	if len(c.Args) < 1 {
+		return types.NewMissingArgsErr(c.NS)
 	}
 
-	var matchedList []common_proto.NamespaceReport
+	appClient, err := c.AppMgr()
+	if err != nil {
+		return err
+	}
 
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	namespaces, err := appClient.NamespaceList(context.Background(), &common_proto.Empty{})
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 	}
-	defer conn.Close()
-	appClient := gwtaskmgr.NewAppMgrClient(conn)
 
-	r, err := appClient.NamespaceList(tokenctx, &common_proto.Empty{})
+	ns, err := lookupNamespace(namespaces.NsReports, c.Args[0])
+	if err != nil {
+		return err
+	}
+
+	apps, err := appClient.AppList(context.Background(), &common_proto.Empty{})
 	if err != nil {
 		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 	}
 
-	for _, nsReport := range r.NsReports {
-		var skip = true
-		if len(matches) == 0 {
-			skip = false
-		} else {
-			for _, m := range matches {
-				if m.Match(nsReport.Namespace.NsName) {
-					skip = false
-				}
-			}
+	item := &displayers.NamespaceDetail{Detail: displayers.NamespaceDetailInfo{
+		Namespace: ns,
+		Apps:      namespaceApps(apps.AppReports, ns.Namespace.NsId),
+	}}
+
+	usages, err := fetchClusterUsages(c)
+	if err != nil {
+		return err
+	}
+	for i := range usages {
+		if usages[i].ID == ns.Namespace.ClusterId {
+			item.Detail.Cluster = &usages[i]
 		}
+	}
 
-		if !skip {
-			matchedList = append(matchedList, *nsReport)
+	if err := c.Display(item); err != nil {
+		return err
+	}
+
+	// the apps and the cluster headroom follow the table
+	if isTableOutput() {
+		fmt.Fprintf(c.Out, "\nApps:\n")
+		if len(item.Detail.Apps) == 0 {
+			fmt.Fprintln(c.Out, "No apps.")
+		} else if err := displayers.WriteTable(&displayers.AppReport{Apps: item.Detail.Apps}, c.Out,
+			"ID", "Name", "ChartName", "ChartVersion", "Status", "Event", "Endpoint"); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(c.Out, "\nCluster %s headroom:\n", ns.Namespace.ClusterName)
+		if item.Detail.Cluster == nil {
+			fmt.Fprintf(c.Out, "Cluster %s reports no capacity.\n", ns.Namespace.ClusterId)
+		} else if err := displayers.WriteTable(&displayers.ClusterResources{Usage: *item.Detail.Cluster,
+			Color: colorTable(c.Out)}, c.Out); err != nil {
+			return err
 		}
 	}
-	item := &displayers.Namespace{Namespaces: matchedList}
-	return c.Display(item)
+	return nil
 }
 
+// namespaceApps returns the apps deployed in the namespace nsID.
+func namespaceApps(apps []*common_proto.AppReport, nsID string) []*common_proto.AppReport {
+	var found []*common_proto.AppReport
+	for _, app := range apps {
+		if app.AppDeployment == nil || app.AppDeployment.Namespace == nil {
+			continue
+		}
+		if app.AppDeployment.Namespace.NsId == nsID {
+			found = append(found, app)
+		}
+	}
 
-// RunNamespaceUpdate update the namespace setting.
+	return found
+}
+
+// RunNamespaceUpdate update the namespace setting. Limits that are not
+// given keep their current value.
 func RunNamespaceUpdate(c *CmdConfig) error {
 
 	if len(c.Args) < 1 {
 		return types.NewMissingArgsErr(c.NS)
 	}
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
+	var err error
+	limits := NamespaceRef{}
+	if limits.CpuLimit, err = c.Ankr.GetString(c.NS, types.ArgNsCpuLimitSlug); err != nil {
+		return err
 	}
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+	if limits.MemLimit, err = c.Ankr.GetString(c.NS, types.ArgNsMemLimitSlug); err != nil {
+		return err
+	}
+	if limits.StorageLimit, err = c.Ankr.GetString(c.NS, types.ArgNsStorageLimitSlug); err != nil {
+		return err
+	}
+	if !limits.hasLimits() {
+		return fmt.Errorf("at least one of --%s, --%s or --%s is required",
+			types.ArgNsCpuLimitSlug, types.ArgNsMemLimitSlug, types.ArgNsStorageLimitSlug)
 	}
-	defer conn.Close()
 
-	appClient := gwtaskmgr.NewAppMgrClient(conn)
+	dryRun, err := c.Ankr.GetBool(c.NS, types.ArgDryRunSlug)
 	if err != nil {
 		return err
 	}
 
-	updateNamespaceRequest := &gwtaskmgr.UpdateNamespaceRequest{}
-	updateNamespaceRequest.NsId = c.Args[0]
-
-	cpuLimit, err := c.Ankr.GetString(c.NS, types.ArgNsCpuLimitSlug)
+	appClient, err := c.AppMgr()
 	if err != nil {
 		return err
 	}
-	nsCpuLimit, err := strconv.ParseUint(cpuLimit, 10, 64)
+
+	namespaces, err := appClient.NamespaceList(context.Background(), &common_proto.Empty{})
 	if err != nil {
-		return err
+		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 	}
 
-	updateNamespaceRequest.NsCpuLimit = uint32(nsCpuLimit)
+	if !dryRun {
+		b, err := newBatch(c, "update")
+		if err != nil {
+			return err
+		}
 
-	memLimit, err := c.Ankr.GetString(c.NS, types.ArgNsMemLimitSlug)
-	if err != nil {
-		return err
+		results := b.run(c.Args, func(id string) (string, error) {
+			ns, err := lookupNamespace(namespaces.NsReports, id)
+			if err != nil {
+				return "", err
+			}
+
+			req, _, err := namespaceUpdateRequest(ns.Namespace, limits)
+			if err != nil || req == nil {
+				return ns.Namespace.NsId, err
+			}
+
+			_, err = appClient.UpdateNamespace(context.Background(), req)
+			return ns.Namespace.NsId, err
+		})
+
+		return c.displayBatch(results)
 	}
-	nsMemLimit, err := strconv.ParseUint(memLimit, 10, 64)
+
+	usages, err := fetchClusterUsages(c)
 	if err != nil {
 		return err
 	}
-	updateNamespaceRequest.NsMemLimit = uint32(nsMemLimit)
 
-	storageLimit, err := c.Ankr.GetString(c.NS, types.ArgNsStorageLimitSlug)
-	if err != nil {
-		return err
+	resizes := []displayers.NamespaceResizeInfo{}
+	for _, id := range c.Args {
+		ns, err := lookupNamespace(namespaces.NsReports, id)
+		if err != nil {
+			return err
+		}
+
+		req, _, err := namespaceUpdateRequest(ns.Namespace, limits)
+		if err != nil {
+			return err
+		}
+		if req == nil {
+			continue
+		}
+
+		var cluster *displayers.ClusterUsageInfo
+		for i := range usages {
+			if usages[i].ID == ns.Namespace.ClusterId {
+				cluster = &usages[i]
+			}
+		}
+		resizes = append(resizes, planNamespaceResize(ns.Namespace, req, cluster)...)
 	}
 
-	nsStorageLimit, err := strconv.ParseUint(storageLimit, 10, 64)
-	if err != nil {
+	overflows := fitClusterGrowth(resizes)
+	if err := c.Display(&displayers.NamespaceResize{Resizes: resizes}); err != nil {
 		return err
 	}
-	updateNamespaceRequest.NsStorageLimit = uint32(nsStorageLimit)
+	for _, g := range overflows {
+		amount := displayers.NamespaceResizeInfo{Resource: g.Resource}.FormatAmount(g.Growth)
+		if len(g.Names) == 1 {
+			warn(fmt.Sprintf("namespace %s needs %s more %s than cluster %s has free",
+				g.Names[0], amount, strings.ToLower(g.Resource), g.Cluster))
+		} else {
+			warn(fmt.Sprintf("namespaces %s need %s more %s in total than cluster %s has free",
+				strings.Join(g.Names, ", "), amount, strings.ToLower(g.Resource), g.Cluster))
+		}
+	}
+	return nil
+}
 
-	fn := func(ids []string) error {
-		for _, id := range ids {
-			_, err := appClient.UpdateNamespace(tokenctx, updateNamespaceRequest)
-			if err != nil {
-				return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
-			}
-			fmt.Printf("Namespace %s update success.\n", id)
+// clusterGrowth is the total growth of one resource of the namespaces
+// resized in a cluster.
+type clusterGrowth struct {
+	Cluster  string
+	Resource string
+	Names    []string
+	Growth   float64
+	Free     float64
+}
+
+// fitClusterGrowth sums the growth of resizes by cluster and resource, as
+// namespaces resized together share the free capacity of their cluster. A
+// growing resize fits only when the total of its cluster does. It returns
+// the totals that do not fit.
+func fitClusterGrowth(resizes []displayers.NamespaceResizeInfo) []clusterGrowth {
+	var growths []*clusterGrowth
+	byKey := map[string]*clusterGrowth{}
+	for _, r := range resizes {
+		if r.ClusterFree == nil || r.New <= r.Current {
+			continue
+		}
+
+		key := r.Cluster + "/" + r.Resource
+		g, ok := byKey[key]
+		if !ok {
+			g = &clusterGrowth{Cluster: r.Cluster, Resource: r.Resource, Free: *r.ClusterFree}
+			byKey[key] = g
+			growths = append(growths, g)
+		}
+		g.Names = append(g.Names, r.Name)
+		g.Growth += r.New - r.Current
+	}
+
+	for i := range resizes {
+		r := &resizes[i]
+		if g, ok := byKey[r.Cluster+"/"+r.Resource]; ok && r.ClusterFree != nil && r.New > r.Current {
+			r.Fits = g.Growth <= g.Free
+		}
+	}
+
+	overflows := []clusterGrowth{}
+	for _, g := range growths {
+		if g.Growth > g.Free {
+			overflows = append(overflows, *g)
 		}
-		return nil
 	}
 
-	return fn(c.Args)
+	return overflows
+}
+
+// planNamespaceResize lists the limits req changes on live and whether the
+// growth of each fits in the free capacity of its cluster, if known.
+func planNamespaceResize(live *common_proto.Namespace, req *gwtaskmgr.UpdateNamespaceRequest,
+	cluster *displayers.ClusterUsageInfo) []displayers.NamespaceResizeInfo {
+
+	resizes := []displayers.NamespaceResizeInfo{}
+	for _, r := range []struct {
+		resource  string
+		current   uint32
+		new       uint32
+		scale     float64
+		clusterOf func(u *displayers.ClusterUsageInfo) displayers.ResourceUsage
+	}{
+		{"CPU", live.NsCpuLimit, req.NsCpuLimit, types.MilliCPUPerCPU,
+			func(u *displayers.ClusterUsageInfo) displayers.ResourceUsage { return u.CPU }},
+		{"Memory", live.NsMemLimit, req.NsMemLimit, types.MiBPerGiB,
+			func(u *displayers.ClusterUsageInfo) displayers.ResourceUsage { return u.Memory }},
+		{"Storage", live.NsStorageLimit, req.NsStorageLimit, types.MiBPerGiB,
+			func(u *displayers.ClusterUsageInfo) displayers.ResourceUsage { return u.Storage }},
+	} {
+		if r.current == r.new {
+			continue
+		}
+
+		resize := displayers.NamespaceResizeInfo{
+			ID:       live.NsId,
+			Name:     live.NsName,
+			Cluster:  live.ClusterName,
+			Resource: r.resource,
+			Current:  float64(r.current) / r.scale,
+			New:      float64(r.new) / r.scale,
+			Fits:     true,
+		}
+		if cluster != nil {
+			free := r.clusterOf(cluster).Free
+			resize.ClusterFree = &free
+			resize.Fits = resize.New-resize.Current <= free
+		}
+
+		resizes = append(resizes, resize)
+	}
 
+	return resizes
+}
+
+// lookupNamespace finds a namespace by ID or, failing that, by name.
+func lookupNamespace(namespaces []*common_proto.NamespaceReport, arg string) (*common_proto.NamespaceReport, error) {
+	ns, err := findNamespace(namespaces, NamespaceRef{ID: arg})
+	if err == nil && ns == nil {
+		ns, err = findNamespace(namespaces, NamespaceRef{Name: arg})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ns == nil {
+		return nil, fmt.Errorf("namespace %s not found", arg)
+	}
+
+	return ns, nil
 }
 
 // RunNamespaceDelete delete a namespace.
@@ -334,48 +494,28 @@ func RunNamespaceDelete(c *CmdConfig) error {
 		return types.NewMissingArgsErr(c.NS)
 	}
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-
 	force, err := c.Ankr.GetBool(c.NS, types.ArgForce)
 	if err != nil {
 		return err
 	}
 
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
 	if force || AskForConfirm(fmt.Sprintf("Are you sure you want to Cancel %d namespace(s) (y/N) ? ", len(c.Args))) == nil {
-		url := viper.GetString("hub-url")
-
-		conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+		appClient, err := c.AppMgr()
 		if err != nil {
-			log.Fatalf("Did not connect: %v", err)
+			return err
 		}
 
-		defer conn.Close()
-		appClient := gwtaskmgr.NewAppMgrClient(conn)
-
-		fn := func(ids []string) error {
-			for _, id := range ids {
-				_, err := appClient.DeleteNamespace(tokenctx, &gwtaskmgr.DeleteNamespaceRequest{NsId: id})
-				if err != nil {
-					return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
-				}
-				fmt.Printf("Namespace %s delete success.\n", id)
-			}
-			return nil
+		b, err := newBatch(c, "delete")
+		if err != nil {
+			return err
 		}
 
-		return fn(c.Args)
+		results := b.run(c.Args, func(id string) (string, error) {
+			_, err := appClient.DeleteNamespace(context.Background(), &gwtaskmgr.DeleteNamespaceRequest{NsId: id})
+			return id, err
+		})
+
+		return c.displayBatch(results)
 	}
 
 	return nil
@@ -0,0 +1,242 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ankr-network/ankrctl/commands/displayers"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	gwdcmgr "github.com/Ankr-network/dccn-common/protos/gateway/dcmgr/v1"
+	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeAppMgr answers the list and detail calls of the app manager, the other
+// calls panic.
+type fakeAppMgr struct {
+	gwtaskmgr.AppMgrClient
+	apps       []*common_proto.AppReport
+	namespaces []*common_proto.NamespaceReport
+	charts     []*common_proto.Chart
+
+	// values holds the values.yaml of the charts ChartDetail knows, by
+	// repo/name@version, whose app version is v<version>. Without it every
+	// chart has two versions.
+	values map[string]string
+
+	// detailErr is returned by AppDetail, which otherwise looks up apps
+	detailErr error
+
+	// chartDetails are the charts ChartDetail was called for, as repo/name
+	chartDetails []string
+	saved        []*gwtaskmgr.SaveAsChartRequest
+}
+
+func (f *fakeAppMgr) AppList(ctx context.Context, in *common_proto.Empty, opts ...grpc.CallOption) (*gwtaskmgr.AppListResponse, error) {
+	return &gwtaskmgr.AppListResponse{AppReports: f.apps}, nil
+}
+
+func (f *fakeAppMgr) AppDetail(ctx context.Context, in *gwtaskmgr.AppID, opts ...grpc.CallOption) (*gwtaskmgr.AppDetailResponse, error) {
+	if f.detailErr != nil {
+		return nil, f.detailErr
+	}
+
+	for _, app := range f.apps {
+		if app.AppDeployment.AppId == in.AppId {
+			return &gwtaskmgr.AppDetailResponse{AppReport: app}, nil
+		}
+	}
+
+	return nil, status.Error(codes.NotFound, "app not found")
+}
+
+func (f *fakeAppMgr) NamespaceList(ctx context.Context, in *common_proto.Empty, opts ...grpc.CallOption) (*gwtaskmgr.NamespaceListResponse, error) {
+	return &gwtaskmgr.NamespaceListResponse{NsReports: f.namespaces}, nil
+}
+
+func (f *fakeAppMgr) ChartList(ctx context.Context, in *gwtaskmgr.ChartListRequest, opts ...grpc.CallOption) (*gwtaskmgr.ChartListResponse, error) {
+	return &gwtaskmgr.ChartListResponse{Charts: f.charts}, nil
+}
+
+func (f *fakeAppMgr) ChartDetail(ctx context.Context, in *gwtaskmgr.ChartDetailRequest, opts ...grpc.CallOption) (*gwtaskmgr.ChartDetailResponse, error) {
+	f.chartDetails = append(f.chartDetails, in.ChartRepo+"/"+in.ChartName)
+	r := &gwtaskmgr.ChartDetailResponse{ChartRepo: in.ChartRepo, ChartName: in.ChartName}
+	if f.values == nil {
+		r.ChartVersionDetails = []*gwtaskmgr.ChartVersionDetail{{ChartVer: "1.0.0"}, {ChartVer: "1.1.0"}}
+		return r, nil
+	}
+
+	prefix := in.ChartRepo + "/" + in.ChartName + "@"
+	for k := range f.values {
+		if strings.HasPrefix(k, prefix) {
+			v := strings.TrimPrefix(k, prefix)
+			r.ChartVersionDetails = append(r.ChartVersionDetails, &gwtaskmgr.ChartVersionDetail{ChartVer: v, ChartAppVer: "v" + v})
+		}
+	}
+	if in.ChartVer == "" && len(r.ChartVersionDetails) > 0 {
+		return r, nil
+	}
+
+	values, ok := f.values[prefix+in.ChartVer]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "chart not found")
+	}
+
+	// the values.yaml is set whatever the type of the field
+	field := reflect.ValueOf(r).Elem().FieldByName("ValuesYaml")
+	field.Set(reflect.ValueOf(values).Convert(field.Type()))
+	return r, nil
+}
+
+func (f *fakeAppMgr) SaveAsChart(ctx context.Context, in *gwtaskmgr.SaveAsChartRequest, opts ...grpc.CallOption) (*common_proto.Empty, error) {
+	f.saved = append(f.saved, in)
+	return &common_proto.Empty{}, nil
+}
+
+// fakeDCAPI answers DataCenterList, the other calls panic.
+type fakeDCAPI struct {
+	gwdcmgr.DCAPIClient
+	clusters []*common_proto.DataCenterStatus
+}
+
+func (f *fakeDCAPI) DataCenterList(ctx context.Context, in *common_proto.Empty, opts ...grpc.CallOption) (*gwdcmgr.DataCenterListResponse, error) {
+	return &gwdcmgr.DataCenterListResponse{DcList: f.clusters}, nil
+}
+
+// fakeHubConfig returns a CmdConfig whose hub clients are appMgr and dcAPI.
+func fakeHubConfig(out *bytes.Buffer, appMgr *fakeAppMgr, dcAPI *fakeDCAPI, args ...string) *CmdConfig {
+	return &CmdConfig{
+		Ankr:   testConfig{},
+		Out:    out,
+		Args:   args,
+		AppMgr: func() (gwtaskmgr.AppMgrClient, error) { return appMgr, nil },
+		DCAPI:  func() (gwdcmgr.DCAPIClient, error) { return dcAPI, nil },
+	}
+}
+
+func TestPlanNamespaceResize(t *testing.T) {
+	live := &common_proto.Namespace{NsId: "ns-1", NsName: "team-a", ClusterName: "demo-cluster",
+		NsCpuLimit: 1000, NsMemLimit: 2048, NsStorageLimit: 10240}
+
+	req, _, err := namespaceUpdateRequest(live, NamespaceRef{CpuLimit: "3", MemLimit: "1Gi"})
+	assert.NoError(t, err)
+
+	resizes := planNamespaceResize(live, req, nil)
+	assert.Len(t, resizes, 2)
+	assert.Equal(t, "CPU", resizes[0].Resource)
+	assert.Equal(t, 1.0, resizes[0].Current)
+	assert.Equal(t, 3.0, resizes[0].New)
+	assert.Nil(t, resizes[0].ClusterFree)
+	assert.Equal(t, "Memory", resizes[1].Resource)
+	assert.Equal(t, 1.0, resizes[1].New)
+
+	cluster := &displayers.ClusterUsageInfo{
+		CPU:    displayers.NewResourceUsage(3, 4),
+		Memory: displayers.NewResourceUsage(16, 16),
+	}
+	resizes = planNamespaceResize(live, req, cluster)
+	assert.False(t, resizes[0].Fits, "growing by 2 vCPUs does not fit in 1 free vCPU")
+	assert.True(t, resizes[1].Fits, "shrinking always fits")
+}
+
+func TestFitClusterGrowth(t *testing.T) {
+	free := func(v float64) *float64 { return &v }
+	resizes := []displayers.NamespaceResizeInfo{
+		{Name: "team-a", Cluster: "demo", Resource: "CPU", Current: 1, New: 2, ClusterFree: free(1.5)},
+		{Name: "team-b", Cluster: "demo", Resource: "CPU", Current: 1, New: 2, ClusterFree: free(1.5)},
+		{Name: "team-c", Cluster: "demo", Resource: "CPU", Current: 2, New: 1, ClusterFree: free(1.5)},
+		{Name: "team-a", Cluster: "demo", Resource: "Memory", Current: 1, New: 2, ClusterFree: free(4)},
+		{Name: "team-d", Cluster: "other", Resource: "CPU", Current: 1, New: 2, ClusterFree: free(1.5)},
+		{Name: "team-e", Cluster: "unknown", Resource: "CPU", Current: 1, New: 8},
+	}
+	for i := range resizes {
+		resizes[i].Fits = resizes[i].ClusterFree == nil || resizes[i].New-resizes[i].Current <= *resizes[i].ClusterFree
+	}
+
+	overflows := fitClusterGrowth(resizes)
+
+	var fits []bool
+	for _, r := range resizes {
+		fits = append(fits, r.Fits)
+	}
+	assert.Equal(t, []bool{false, false, true, true, true, true}, fits,
+		"team-a and team-b fit alone but not together, shrinking always fits")
+	assert.Equal(t, []clusterGrowth{
+		{Cluster: "demo", Resource: "CPU", Names: []string{"team-a", "team-b"}, Growth: 2, Free: 1.5},
+	}, overflows)
+}
+
+func testApp(id, name, nsID string) *common_proto.AppReport {
+	return &common_proto.AppReport{AppDeployment: &common_proto.AppDeployment{
+		AppId: id, AppName: name,
+		Namespace:   &common_proto.Namespace{NsId: nsID},
+		ChartDetail: &common_proto.ChartDetail{ChartRepo: "stable", ChartName: "wordpress", ChartVer: "5.6.0"},
+		Attributes: &common_proto.AppAttributes{
+			CreationDate: &timestamp.Timestamp{}, LastModifiedDate: &timestamp.Timestamp{},
+		},
+	}}
+}
+
+func TestRunNamespaceDescribe(t *testing.T) {
+	appMgr := &fakeAppMgr{
+		namespaces: []*common_proto.NamespaceReport{
+			{Namespace: &common_proto.Namespace{NsId: "ns-1", NsName: "team-a", ClusterId: "dc-1", ClusterName: "demo-cluster",
+				NsCpuLimit: 1500, NsMemLimit: 2048, NsStorageLimit: 10240}},
+			{Namespace: &common_proto.Namespace{NsId: "ns-2", NsName: "team-b", ClusterId: "dc-2", ClusterName: "other-cluster"}},
+		},
+		apps: []*common_proto.AppReport{
+			testApp("app-1", "web", "ns-1"),
+			testApp("app-2", "db", "ns-2"),
+		},
+	}
+	dcAPI := &fakeDCAPI{clusters: []*common_proto.DataCenterStatus{
+		{DcId: "dc-1", DcName: "demo-cluster", DcHeartbeatReport: &common_proto.DCHeartbeatReport{
+			Metrics: `{"TotalCPU":4000,"UsedCPU":1000,"TotalMemory":8192,"UsedMemory":2048,"TotalStorage":102400,"UsedStorage":0}`,
+		}},
+	}}
+
+	var out bytes.Buffer
+	assert.NoError(t, RunNamespaceDescribe(fakeHubConfig(&out, appMgr, dcAPI, "team-a")))
+	got := out.String()
+	assert.Contains(t, got, "team-a")
+	assert.Contains(t, got, "1.50 vCPU(s)")
+	assert.Contains(t, got, "10.00GiB")
+	assert.Contains(t, got, "\nApps:\n")
+	assert.Contains(t, got, "app-1")
+	assert.NotContains(t, got, "app-2", "apps of other namespaces are left out")
+	assert.Contains(t, got, "Cluster demo-cluster headroom:")
+	assert.Contains(t, got, "3.00 vCPU(s)")
+	assert.NotContains(t, got, "reports no capacity")
+
+	out.Reset()
+	assert.NoError(t, RunNamespaceDescribe(fakeHubConfig(&out, appMgr, dcAPI, "ns-2")))
+	got = out.String()
+	assert.Contains(t, got, "team-b")
+	assert.Contains(t, got, "app-2")
+	assert.Contains(t, got, "Cluster dc-2 reports no capacity.")
+
+	out.Reset()
+	err := RunNamespaceDescribe(fakeHubConfig(&out, appMgr, dcAPI, "team-c"))
+	assert.EqualError(t, err, "namespace team-c not found")
+	assert.Empty(t, out.String())
+}
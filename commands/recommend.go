@@ -0,0 +1,264 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Ankr-network/ankrctl/commands/displayers"
+	"github.com/Ankr-network/ankrctl/types"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"google.golang.org/grpc/status"
+)
+
+// autoClusterID asks for the recommended cluster in place of a cluster ID.
+const autoClusterID = "auto"
+
+// earthRadiusKm is the mean radius of the earth used for distances.
+const earthRadiusKm = 6371.0
+
+// capacityRequest is the capacity a namespace needs, in vCPUs and GiB, and
+// where it would preferably run.
+type capacityRequest struct {
+	CPU     float64
+	Memory  float64
+	Storage float64
+	Near    *geoPoint
+
+	// NameContains keeps only the clusters whose name contains it
+	NameContains string
+}
+
+type geoPoint struct {
+	Lat, Lng float64
+}
+
+// parseGeoPoint parses a location given as lat,lng.
+func parseGeoPoint(s string) (*geoPoint, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid location %q, use lat,lng such as 37.77,-122.42", s)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("invalid latitude %q", parts[0])
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return nil, fmt.Errorf("invalid longitude %q", parts[1])
+	}
+
+	return &geoPoint{Lat: lat, Lng: lng}, nil
+}
+
+// distanceKm returns the great-circle distance between two points.
+func distanceKm(a, b geoPoint) float64 {
+	rad := math.Pi / 180
+	dLat := (b.Lat - a.Lat) * rad
+	dLng := (b.Lng - a.Lng) * rad
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(a.Lat*rad)*math.Cos(b.Lat*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// clusterAvailable reports whether a cluster takes new namespaces, judging by
+// its status.
+func clusterAvailable(status string) bool {
+	for _, s := range []string{"unavailable", "offline", "down", "fail"} {
+		if strings.Contains(status, s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rankClusters ranks the available clusters for req. Clusters with enough
+// free capacity come first, ordered by distance from req.Near when given
+// and then by the headroom left after placing the namespace, which is the
+// smallest share of CPU, memory or storage that would remain free.
+func rankClusters(usages []displayers.ClusterUsageInfo, req capacityRequest) []displayers.ClusterCandidateInfo {
+	var candidates []displayers.ClusterCandidateInfo
+	for _, u := range usages {
+		if !clusterAvailable(u.Status) {
+			continue
+		}
+		if req.NameContains != "" && !strings.Contains(strings.ToLower(u.Name), strings.ToLower(req.NameContains)) {
+			continue
+		}
+
+		c := displayers.ClusterCandidateInfo{
+			Usage: u,
+			Fits:  u.CPU.Free >= req.CPU && u.Memory.Free >= req.Memory && u.Storage.Free >= req.Storage,
+		}
+
+		c.Headroom = 100
+		for _, r := range []struct {
+			usage displayers.ResourceUsage
+			want  float64
+		}{
+			{u.CPU, req.CPU}, {u.Memory, req.Memory}, {u.Storage, req.Storage},
+		} {
+			left := 0.0
+			if r.usage.Total > 0 {
+				left = (r.usage.Free - r.want) / r.usage.Total * 100
+			}
+			c.Headroom = math.Min(c.Headroom, left)
+		}
+
+		if req.Near != nil {
+			lat, errLat := strconv.ParseFloat(u.Lat, 64)
+			lng, errLng := strconv.ParseFloat(u.Lng, 64)
+			if errLat == nil && errLng == nil {
+				d := distanceKm(*req.Near, geoPoint{Lat: lat, Lng: lng})
+				c.Distance = &d
+			}
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Fits != b.Fits {
+			return a.Fits
+		}
+		if req.Near != nil && (a.Distance == nil) != (b.Distance == nil) {
+			return a.Distance != nil
+		}
+		if req.Near != nil && a.Distance != nil && b.Distance != nil && *a.Distance != *b.Distance {
+			return *a.Distance < *b.Distance
+		}
+		return a.Headroom > b.Headroom
+	})
+
+	for i := range candidates {
+		candidates[i].Rank = i + 1
+	}
+
+	return candidates
+}
+
+// recommendCluster returns the best cluster for a namespace with the given
+// limits, in the mCPUs and MiB used by the hub.
+func recommendCluster(c *CmdConfig, cpu, mem, storage uint32) (*displayers.ClusterCandidateInfo, error) {
+	usages, err := fetchClusterUsages(c)
+	if err != nil {
+		return nil, err
+	}
+
+	req := capacityRequest{CPU: float64(cpu) / types.MilliCPUPerCPU, Memory: float64(mem) / types.MiBPerGiB, Storage: float64(storage) / types.MiBPerGiB}
+	candidates := rankClusters(usages, req)
+	if len(candidates) == 0 || !candidates[0].Fits {
+		return nil, fmt.Errorf("no cluster has %.2f vCPU(s), %.2fGiB memory and %.2fGiB storage free",
+			req.CPU, req.Memory, req.Storage)
+	}
+
+	return &candidates[0], nil
+}
+
+// resolveClusterID returns id, or the recommended cluster for the limits
+// when id is auto.
+func resolveClusterID(c *CmdConfig, id string, cpu, mem, storage uint32) (string, error) {
+	if id != autoClusterID {
+		return id, nil
+	}
+
+	best, err := recommendCluster(c, cpu, mem, storage)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(os.Stderr, "Using cluster %s (%s)\n", best.Usage.Name, best.Usage.ID)
+	return best.Usage.ID, nil
+}
+
+func fetchClusterUsages(c *CmdConfig) ([]displayers.ClusterUsageInfo, error) {
+	dcMgr, err := c.DCAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := dcMgr.DataCenterList(context.Background(), &common_proto.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+	}
+
+	var usages []displayers.ClusterUsageInfo
+	for _, dc := range r.DcList {
+		usages = append(usages, clusterUsage(dc))
+	}
+
+	return usages, nil
+}
+
+// optionalLimit reads a limit flag that may be left empty.
+func optionalLimit(c *CmdConfig, key string, parse func(string) (uint32, error)) (uint32, error) {
+	s, err := c.Ankr.GetString(c.NS, key)
+	if err != nil || s == "" {
+		return 0, err
+	}
+
+	return parse(s)
+}
+
+// RunClusterRecommend ranks the clusters for a namespace request.
+func RunClusterRecommend(c *CmdConfig) error {
+
+	cpu, err := optionalLimit(c, types.ArgNsCpuLimitSlug, parseCpuLimit)
+	if err != nil {
+		return err
+	}
+	mem, err := optionalLimit(c, types.ArgNsMemLimitSlug, parseMemLimit)
+	if err != nil {
+		return err
+	}
+	storage, err := optionalLimit(c, types.ArgNsStorageLimitSlug, parseStorageLimit)
+	if err != nil {
+		return err
+	}
+
+	req := capacityRequest{CPU: float64(cpu) / types.MilliCPUPerCPU, Memory: float64(mem) / types.MiBPerGiB, Storage: float64(storage) / types.MiBPerGiB}
+
+	near, err := c.Ankr.GetString(c.NS, types.ArgNearSlug)
+	if err != nil {
+		return err
+	}
+	if near != "" {
+		if req.Near, err = parseGeoPoint(near); err != nil {
+			return err
+		}
+	}
+
+	if req.NameContains, err = c.Ankr.GetString(c.NS, types.ArgNameContainsSlug); err != nil {
+		return err
+	}
+
+	usages, err := fetchClusterUsages(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Display(&displayers.ClusterRecommendation{Candidates: rankClusters(usages, req)})
+}
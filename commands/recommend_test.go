@@ -0,0 +1,76 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/Ankr-network/ankrctl/commands/displayers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankClusters(t *testing.T) {
+	usage := func(id, status, lat, lng string, cpu, mem, storage float64) displayers.ClusterUsageInfo {
+		return displayers.ClusterUsageInfo{
+			ID:      id,
+			Name:    id,
+			Status:  status,
+			Lat:     lat,
+			Lng:     lng,
+			CPU:     displayers.NewResourceUsage(4-cpu, 4),
+			Memory:  displayers.NewResourceUsage(16-mem, 16),
+			Storage: displayers.NewResourceUsage(100-storage, 100),
+		}
+	}
+
+	usages := []displayers.ClusterUsageInfo{
+		usage("small", "dcstatus_ok", "37.77", "-122.42", 1, 2, 10),
+		usage("large", "dcstatus_ok", "51.51", "-0.13", 3, 12, 80),
+		usage("roomy", "dcstatus_ok", "", "", 4, 16, 100),
+		usage("down", "dcstatus_unavailable", "37.77", "-122.42", 4, 16, 100),
+	}
+
+	ids := func(candidates []displayers.ClusterCandidateInfo) []string {
+		var out []string
+		for _, c := range candidates {
+			out = append(out, c.Usage.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		req  capacityRequest
+		want []string
+	}{
+		{name: "headroom", req: capacityRequest{CPU: 2, Memory: 4, Storage: 20}, want: []string{"roomy", "large", "small"}},
+		{name: "near", req: capacityRequest{CPU: 0.5, Near: &geoPoint{Lat: 37, Lng: -122}}, want: []string{"small", "large", "roomy"}},
+		{name: "name contains", req: capacityRequest{NameContains: "LARG"}, want: []string{"large"}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ids(rankClusters(usages, tt.req)), tt.name)
+	}
+}
+
+func TestParseGeoPoint(t *testing.T) {
+	p, err := parseGeoPoint("37.77, -122.42")
+	assert.NoError(t, err)
+	assert.Equal(t, &geoPoint{Lat: 37.77, Lng: -122.42}, p)
+
+	for _, in := range []string{"", "37.77", "91,0", "0,181", "a,b"} {
+		_, err := parseGeoPoint(in)
+		assert.Error(t, err, in)
+	}
+}
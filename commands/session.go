@@ -0,0 +1,147 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	gwusermgr "github.com/Ankr-network/dccn-common/protos/gateway/usermgr/v1"
+)
+
+// tokenExpirySkew is how long before its expiry an access token is already
+// treated as expired, so it does not run out while a call is in flight.
+const tokenExpirySkew = 30 * time.Second
+
+// ErrNoRefreshToken is an error for when an access token must be refreshed
+// but 'user login' saved no refresh token.
+var ErrNoRefreshToken = errors.New("no ankr network refresh token found, please login again")
+
+// tokenManager owns the tokens of an auth context. It hands out the access
+// token for hub calls and renews it with the refresh token once it expires,
+// saving the new tokens to the config file.
+type tokenManager struct {
+	context string
+	userMgr func() (gwusermgr.UserMgrClient, error)
+
+	// refreshMu serializes refreshes. It is never held together with mu,
+	// as the refresh call itself asks for the current token.
+	refreshMu  sync.Mutex
+	mu         sync.Mutex
+	authResult *gwusermgr.AuthenticationResult
+}
+
+// newTokenManager creates a tokenManager for the tokens saved for the auth
+// context name. userMgr is used to call RefreshSession.
+func newTokenManager(name string, userMgr func() (gwusermgr.UserMgrClient, error)) *tokenManager {
+	authResult := contextAuthResult(name)
+
+	return &tokenManager{
+		context:    name,
+		userMgr:    userMgr,
+		authResult: &authResult,
+	}
+}
+
+// Token returns the current access token.
+func (t *tokenManager) Token() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.authResult.AccessToken
+}
+
+// Expiry returns when the current access token expires, if it says so.
+func (t *tokenManager) Expiry() (time.Time, bool) {
+	return tokenExpiry(t.Token())
+}
+
+// Refresh replaces the access token stale with a new one. If stale was
+// already replaced by a concurrent call, the newer token is kept as is.
+func (t *tokenManager) Refresh(ctx context.Context, stale string) error {
+	t.refreshMu.Lock()
+	defer t.refreshMu.Unlock()
+
+	t.mu.Lock()
+	accessToken, refreshToken := t.authResult.AccessToken, t.authResult.RefreshToken
+	t.mu.Unlock()
+
+	if accessToken != stale {
+		return nil
+	}
+	if refreshToken == "" {
+		return ErrNoRefreshToken
+	}
+
+	userClient, err := t.userMgr()
+	if err != nil {
+		return err
+	}
+
+	rsp, err := userClient.RefreshSession(ctx,
+		&gwusermgr.RefreshToken{RefreshToken: refreshToken})
+	if err != nil {
+		return fmt.Errorf("unable to refresh access token: %v", err)
+	}
+	if rsp.RefreshToken == "" {
+		rsp.RefreshToken = refreshToken
+	}
+
+	t.mu.Lock()
+	t.authResult = rsp
+	t.mu.Unlock()
+
+	setContextValue(t.context, authResultKey, rsp)
+	return writeConfig()
+}
+
+// tokenExpiry returns the expiry time carried in the "exp" claim of a JWT
+// access token.
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	claims := struct {
+		Exp float64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp <= 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(claims.Exp), 0), true
+}
+
+// tokenExpired reports whether token is expired, or about to, at now. A
+// token without a readable expiry is left for the hub to judge.
+func tokenExpired(token string, now time.Time) bool {
+	exp, ok := tokenExpiry(token)
+	if !ok {
+		return false
+	}
+
+	return !now.Add(tokenExpirySkew).Before(exp)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenExpiry(t *testing.T) {
+	jwt := func(payload string) string {
+		return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2ln"
+	}
+
+	exp, ok := tokenExpiry(jwt(`{"exp":1553242418,"iss":"ankr.network"}`))
+	assert.True(t, ok)
+	assert.Equal(t, int64(1553242418), exp.Unix())
+
+	_, ok = tokenExpiry(jwt(`{"iss":"ankr.network"}`))
+	assert.False(t, ok)
+
+	_, ok = tokenExpiry("not-a-jwt")
+	assert.False(t, ok)
+
+	now := time.Unix(1553242418, 0)
+	assert.True(t, tokenExpired(jwt(`{"exp":1553242418}`), now))
+	assert.True(t, tokenExpired(jwt(`{"exp":1553242428}`), now), "expected skew to apply")
+	assert.False(t, tokenExpired(jwt(`{"exp":1553246018}`), now))
+	assert.False(t, tokenExpired("opaque-token", now))
+}
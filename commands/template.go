@@ -0,0 +1,205 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Ankr-network/ankrctl/types"
+	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
+	"google.golang.org/grpc/status"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// Release names used when rendering a chart without an app.
+const (
+	defaultReleaseName      = "release-name"
+	defaultReleaseNamespace = "default"
+)
+
+// RunChartTemplate renders the manifests of a chart locally.
+func RunChartTemplate(c *CmdConfig) error {
+	if len(c.Args) < 1 {
+		return types.NewMissingArgsErr(c.NS)
+	}
+
+	ch, err := loadTemplateChart(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+
+	vals, err := readValues(c, nil)
+	if err != nil {
+		return err
+	}
+
+	release, err := c.Ankr.GetString(c.NS, types.ArgAppNameSlug)
+	if err != nil {
+		return err
+	}
+	if release == "" {
+		release = defaultReleaseName
+	}
+
+	namespace, err := c.Ankr.GetString(c.NS, types.ArgNsNameSlug)
+	if err != nil {
+		return err
+	}
+	if namespace == "" {
+		namespace = defaultReleaseNamespace
+	}
+
+	files, err := renderChart(ch, vals, release, namespace)
+	if err != nil {
+		return err
+	}
+
+	outDir, err := c.Ankr.GetString(c.NS, types.ArgOutputDirSlug)
+	if err != nil {
+		return err
+	}
+	if outDir == "" {
+		return writeManifests(c.Out, files)
+	}
+
+	for _, name := range sortedKeys(files) {
+		dest := filepath.Join(outDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(dest, []byte(files[name]), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.Out, "wrote %s\n", dest)
+	}
+	return nil
+}
+
+// loadTemplateChart loads the chart name from the hub, or from the local
+// chart archive or directory name when no repo and version are given.
+func loadTemplateChart(c *CmdConfig, name string) (*chart.Chart, error) {
+	repo, err := c.Ankr.GetString(c.NS, types.ArgRepoSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := c.Ankr.GetString(c.NS, types.ArgVersionSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	if repo == "" && version == "" {
+		if _, err := os.Stat(name); err == nil {
+			return chartutil.Load(name)
+		}
+	}
+	if repo == "" || version == "" {
+		return nil, fmt.Errorf("--%s and --%s are required unless %s is a local chart",
+			types.ArgRepoSlug, types.ArgVersionSlug, name)
+	}
+
+	version, err = resolveChartVersion(c, name, repo, version)
+	if err != nil {
+		return nil, err
+	}
+
+	appClient, err := c.AppMgr()
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := appClient.DownloadChart(context.Background(), &gwtaskmgr.DownloadChartRequest{
+		ChartName: name,
+		ChartRepo: repo,
+		ChartVer:  version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+	}
+
+	return chartutil.LoadArchive(bytes.NewReader(rsp.ChartFile))
+}
+
+// renderChart renders the templates of ch with vals on top of its default
+// values, for a release in namespace. It returns the manifests by path,
+// leaving out partials, notes and templates that render empty.
+func renderChart(ch *chart.Chart, vals map[string]interface{}, release, namespace string) (map[string]string, error) {
+	raw, err := valuesYaml(vals)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &chart.Config{Raw: string(raw)}
+	if err := chartutil.ProcessRequirementsEnabled(ch, config); err != nil {
+		return nil, err
+	}
+	if err := chartutil.ProcessRequirementsImportValues(ch); err != nil {
+		return nil, err
+	}
+
+	options := chartutil.ReleaseOptions{Name: release, Namespace: namespace, IsInstall: true, Revision: 1}
+	renderVals, err := chartutil.ToRenderValues(ch, config, options)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := engine.New().Render(ch, renderVals)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]string{}
+	for name, content := range out {
+		base := path.Base(name)
+		if strings.HasPrefix(base, "_") || base == "NOTES.txt" || strings.TrimSpace(content) == "" {
+			continue
+		}
+		files[name] = content
+	}
+
+	return files, nil
+}
+
+// writeManifests writes files as one YAML stream, in the order of their
+// paths, each document headed by the template it came from.
+func writeManifests(out io.Writer, files map[string]string) error {
+	for _, name := range sortedKeys(files) {
+		if _, err := fmt.Fprintf(out, "---\n# Source: %s\n%s\n", name, strings.TrimRight(files[name], "\n")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteManifests(t *testing.T) {
+	var out bytes.Buffer
+	err := writeManifests(&out, map[string]string{
+		"web/templates/svc.yaml":    "kind: Service\n",
+		"web/templates/deploy.yaml": "kind: Deployment",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "---\n# Source: web/templates/deploy.yaml\nkind: Deployment\n"+
+		"---\n# Source: web/templates/svc.yaml\nkind: Service\n", out.String())
+}
@@ -15,7 +15,9 @@ package commands
 
 import (
 	"fmt"
-	"log"
+	"io/ioutil"
+	"os"
+	"strings"
 	"syscall"
 	"time"
 
@@ -26,12 +28,11 @@ import (
 
 	"context"
 
+	"github.com/Ankr-network/ankrctl/commands/displayers"
 	"github.com/Ankr-network/ankrctl/types"
 	ankr_const "github.com/Ankr-network/dccn-common"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	gwusermgr "github.com/Ankr-network/dccn-common/protos/gateway/usermgr/v1"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
 )
 
 // userCmd creates the user command.
@@ -102,16 +103,29 @@ func userCmd() *Command {
 	//DCCN-CLI user login
 	cmdUserLogin := CmdBuilder(cmd, RunUserLogin, "login", "user login", Writer,
 		aliasOpt("li"), docCategories("user"))
-	_ = cmdUserLogin
+	AddStringFlag(cmdUserLogin, types.ArgEmailSlug, "", "", "User email, defaults to ANKR_EMAIL")
+	AddBoolFlag(cmdUserLogin, types.ArgPasswordStdinSlug, "", false, "Read the password from stdin")
+	AddStringFlag(cmdUserLogin, types.ArgPasswordFileSlug, "", "", "Read the password from a file")
 
 	//DCCN-CLI user logout
 	cmdUserLogout := CmdBuilder(cmd, RunUserLogout, "logout", "user logout", Writer,
 		aliasOpt("lo"), docCategories("user"))
 	_ = cmdUserLogout
 
+	//DCCN-CLI user refresh
+	cmdUserRefresh := CmdBuilder(cmd, RunUserTokenRefresh, "refresh",
+		"refresh the access token with the saved refresh token", Writer, aliasOpt("rf"), docCategories("user"))
+	_ = cmdUserRefresh
+
+	//DCCN-CLI user whoami
+	cmdUserWhoAmI := CmdBuilder(cmd, RunUserWhoAmI, "whoami", "show the logged in user of the active auth context",
+		Writer, displayerType(&displayers.WhoAmI{ShowExpiry: true}), docCategories("user"))
+	AddBoolFlag(cmdUserWhoAmI, types.ArgShowExpirySlug, "", false, "Show when the access token expires")
+
 	//DCCN-CLI get user detail with wallet address
 	cmdUserDetail := CmdBuilder(cmd, RunUserDetail, "detail",
-		"get user detail with wallet address", Writer, aliasOpt("ud"), docCategories("user"))
+		"get user detail with wallet address", Writer, aliasOpt("ud"), displayerType(&displayers.UserDetail{}),
+		docCategories("user"))
 	_ = cmdUserDetail
 
 	return cmd
@@ -136,14 +150,10 @@ func RunUserRegister(c *CmdConfig) error {
 		return err
 	}
 
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	userClient, err := c.UserMgr()
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
 	ctx, cancel := context.WithTimeout(context.Background(), ankr_const.ClientTimeOut*time.Second)
 	defer cancel()
 
@@ -165,77 +175,158 @@ func RunUserRegister(c *CmdConfig) error {
 // RunUserLogin login user by email and password.
 func RunUserLogin(c *CmdConfig) error {
 
-	fmt.Print("\nEmail: ")
-	email, err := retrieveUserInput()
-	if err != nil {
+	name := currentContext()
+	if err := validateContextName(name); err != nil {
 		return err
 	}
 
-	fmt.Print("\nPassword: ")
-	password, err := terminal.ReadPassword(int(syscall.Stdin))
+	email, password, err := loginCredentials(c)
 	if err != nil {
 		return err
 	}
 
-	url := viper.GetString("hub-url")
-
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	userClient, err := c.UserMgr()
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
 	ctx, cancel := context.WithTimeout(context.Background(),
 		ankr_const.ClientTimeOut*time.Second)
 	defer cancel()
 
 	rsp, err := userClient.Login(ctx,
-		&gwusermgr.LoginRequest{Email: email, Password: string(password)})
+		&gwusermgr.LoginRequest{Email: email, Password: password})
 	if err != nil {
-		log.Fatal(err.Error())
+		return fmt.Errorf("login failed: %v", err)
 	}
 
-	fmt.Printf("\n\nLogin Successful!\n\n")
-	viper.Set("UserDetail", rsp.User)
-	viper.Set("AuthResult", rsp.AuthenticationResult)
+	setContextValue(name, userDetailKey, rsp.User)
+	setContextValue(name, authResultKey, rsp.AuthenticationResult)
+	if name != defaultContext {
+		setContextValue(name, types.ArgHubURL, hubAddr())
+	}
 	if err := writeConfig(); err != nil {
 		return err
 	}
+
+	if !isTableOutput() {
+		identity := displayers.WhoAmIInfo{
+			Context: name,
+			HubURL:  hubAddr(),
+		}
+		if rsp.User != nil {
+			identity.ID = rsp.User.Id
+			identity.Email = rsp.User.Email
+		}
+		if rsp.AuthenticationResult != nil {
+			if exp, ok := tokenExpiry(rsp.AuthenticationResult.AccessToken); ok {
+				identity.ExpiresAt = &exp
+			}
+		}
+
+		return c.Display(&displayers.WhoAmI{Identity: identity, ShowExpiry: true})
+	}
+
+	fmt.Printf("\n\nLogin Successful!\n\n")
+	if name != defaultContext {
+		fmt.Printf("Saved to auth context %q, use 'ankrctl auth context use %s' to make it active.\n", name, name)
+	}
 	return nil
 
 }
 
-// RunUserLogout logout user.
-func RunUserLogout(c *CmdConfig) error {
+// loginCredentials returns the email and password to login with. They are
+// taken from the flags, then from ANKR_EMAIL and ANKR_PASSWORD, and only
+// prompted for when stdin is a terminal.
+func loginCredentials(c *CmdConfig) (string, string, error) {
+
+	email, err := c.Ankr.GetString(c.NS, types.ArgEmailSlug)
+	if err != nil {
+		return "", "", err
+	}
+
+	passwordStdin, err := c.Ankr.GetBool(c.NS, types.ArgPasswordStdinSlug)
+	if err != nil {
+		return "", "", err
+	}
+
+	passwordFile, err := c.Ankr.GetString(c.NS, types.ArgPasswordFileSlug)
+	if err != nil {
+		return "", "", err
+	}
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
+	if passwordStdin && passwordFile != "" {
+		return "", "", fmt.Errorf("--%s and --%s cannot be used together",
+			types.ArgPasswordStdinSlug, types.ArgPasswordFileSlug)
+	}
+
+	if email == "" {
+		email = os.Getenv("ANKR_EMAIL")
+	}
+
+	interactive := terminal.IsTerminal(int(syscall.Stdin)) && !passwordStdin
+
+	if email == "" {
+		if !interactive {
+			return "", "", fmt.Errorf("no email given, use --%s or ANKR_EMAIL", types.ArgEmailSlug)
+		}
+
+		fmt.Fprint(os.Stderr, "\nEmail: ")
+		if email, err = retrieveUserInput(); err != nil {
+			return "", "", err
+		}
+	}
 
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
+	var password string
+	switch {
+	case passwordStdin:
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to read password from stdin: %v", err)
+		}
+		password = strings.TrimRight(string(b), "\r\n")
+	case passwordFile != "":
+		b, err := ioutil.ReadFile(passwordFile)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to read password file: %v", err)
+		}
+		password = strings.TrimRight(string(b), "\r\n")
+	case os.Getenv("ANKR_PASSWORD") != "":
+		password = os.Getenv("ANKR_PASSWORD")
+	case interactive:
+		fmt.Fprint(os.Stderr, "\nPassword: ")
+		b, err := terminal.ReadPassword(int(syscall.Stdin))
+		if err != nil {
+			return "", "", err
+		}
+		password = string(b)
+	default:
+		return "", "", fmt.Errorf("no password given, use --%s, --%s or ANKR_PASSWORD",
+			types.ArgPasswordStdinSlug, types.ArgPasswordFileSlug)
 	}
 
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
+	if password == "" {
+		return "", "", fmt.Errorf("password must not be empty")
+	}
+
+	return email, password, nil
+}
+
+// RunUserLogout logout user.
+func RunUserLogout(c *CmdConfig) error {
+
+	name := currentContext()
+	authResult := contextAuthResult(name)
 
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	userClient, err := c.UserMgr()
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
-	tokenContext, cancel := context.WithTimeout(ctx, 180*time.Second)
-	defer cancel()
-	if _, err := userClient.Logout(tokenContext,
+	if _, err := userClient.Logout(context.Background(),
 		&gwusermgr.RefreshToken{RefreshToken: authResult.RefreshToken}); err != nil {
 		return err
 	}
-	viper.Set("UserDetail", "")
-	viper.Set("AuthResult", "")
+	setContextValue(name, userDetailKey, "")
+	setContextValue(name, authResultKey, "")
 	if err := writeConfig(); err != nil {
 		return err
 	}
@@ -256,14 +347,10 @@ func RunUserConfirmRegistration(c *CmdConfig) error {
 		return err
 	}
 
-	url := viper.GetString("hub-url")
-
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	userClient, err := c.UserMgr()
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
 	if _, err := userClient.ConfirmRegistration(context.Background(),
 		&gwusermgr.ConfirmRegistrationRequest{Email: c.Args[0],
 			ConfirmationCode: confirmationCode}); err != nil {
@@ -281,15 +368,10 @@ func RunUserForgotPassword(c *CmdConfig) error {
 		return types.NewMissingArgsErr(c.NS)
 	}
 
-	url := viper.GetString("hub-url")
-
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	userClient, err := c.UserMgr()
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
 	if _, err := userClient.ForgotPassword(context.Background(),
 		&gwusermgr.ForgotPasswordRequest{Email: c.Args[0]}); err != nil {
 		return err
@@ -317,15 +399,10 @@ func RunUserConfirmPassword(c *CmdConfig) error {
 		return err
 	}
 
-	url := viper.GetString("hub-url")
-
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	userClient, err := c.UserMgr()
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
 	if _, err := userClient.ConfirmPassword(context.Background(),
 		&gwusermgr.ConfirmPasswordRequest{Email: c.Args[0], ConfirmationCode: confirmationCode,
 			NewPassword: confirmPassword}); err != nil {
@@ -340,20 +417,6 @@ func RunUserConfirmPassword(c *CmdConfig) error {
 // RunUserChangePassword change password with new password.
 func RunUserChangePassword(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
 	oldPassword, err := c.Ankr.GetString(c.NS, types.ArgOldPasswordSlug)
 	if err != nil {
 		return err
@@ -364,15 +427,11 @@ func RunUserChangePassword(c *CmdConfig) error {
 		return err
 	}
 
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	userClient, err := c.UserMgr()
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
-	if _, err := userClient.ChangePassword(tokenctx,
+	if _, err := userClient.ChangePassword(context.Background(),
 		&gwusermgr.ChangePasswordRequest{NewPassword: newPassword, OldPassword: oldPassword}); err != nil {
 		return err
 	}
@@ -385,79 +444,74 @@ func RunUserChangePassword(c *CmdConfig) error {
 // RunUserTokenRefresh refresh token with new one.
 func RunUserTokenRefresh(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
+	if c.session == nil {
+		return fmt.Errorf("an access token given with --%s cannot be refreshed", types.ArgAccessToken)
+	}
 
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
+	if err := c.session.Refresh(context.Background(), c.session.Token()); err != nil {
+		return err
 	}
+	fmt.Println("Refresh Session Success.")
 
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
+	return nil
+}
 
-	url := viper.GetString("hub-url")
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("did not connect: %v", err)
-	}
+// RunUserWhoAmI shows the user logged in to the active auth context.
+func RunUserWhoAmI(c *CmdConfig) error {
 
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
-	rsp, err := userClient.RefreshSession(tokenctx,
-		&gwusermgr.RefreshToken{RefreshToken: authResult.RefreshToken})
+	showExpiry, err := c.Ankr.GetBool(c.NS, types.ArgShowExpirySlug)
 	if err != nil {
 		return err
 	}
-	viper.Set("AuthResult", rsp)
-	if err := writeConfig(); err != nil {
-		return err
+
+	name := currentContext()
+	user := contextUserDetail(name)
+
+	identity := displayers.WhoAmIInfo{
+		Context: name,
+		ID:      user.Id,
+		Email:   user.Email,
+		HubURL:  hubAddr(),
 	}
-	fmt.Println("Refresh Session Success.")
 
-	return nil
+	token := Token
+	if c.session != nil {
+		token = c.session.Token()
+	}
+	if token == "" {
+		return ErrNoAccessToken
+	}
+
+	if showExpiry {
+		if exp, ok := tokenExpiry(token); ok {
+			identity.ExpiresAt = &exp
+		}
+	}
+
+	return c.Display(&displayers.WhoAmI{Identity: identity, ShowExpiry: showExpiry})
 }
 
 // RunUserChangeEmail change password with new password.
 func RunUserChangeEmail(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-	user := gwusermgr.User{}
-	viper.UnmarshalKey("User", &user)
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
 	if len(c.Args) < 1 {
 		return types.NewMissingArgsErr(c.NS)
 	}
 
-	url := viper.GetString("hub-url")
+	name := currentContext()
+	user := gwusermgr.User{}
+	viper.UnmarshalKey(contextKey(name, userKey), &user)
 
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	userClient, err := c.UserMgr()
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
-	if _, err := userClient.ChangeEmail(tokenctx,
+	if _, err := userClient.ChangeEmail(context.Background(),
 		&gwusermgr.ChangeEmailRequest{NewEmail: c.Args[0]}); err != nil {
 		return err
 	}
 	user.Email = c.Args[0]
-	viper.Set("User", user)
+	setContextValue(name, userKey, user)
 	if err := writeConfig(); err != nil {
 		return err
 	}
@@ -470,21 +524,6 @@ func RunUserChangeEmail(c *CmdConfig) error {
 // RunUserConfirmEmail confirm user registration.
 func RunUserConfirmEmail(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-	user := gwusermgr.User{}
-	viper.UnmarshalKey("User", &user)
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
 	if len(c.Args) < 1 {
 		return types.NewMissingArgsErr(c.NS)
 	}
@@ -494,15 +533,11 @@ func RunUserConfirmEmail(c *CmdConfig) error {
 		return err
 	}
 
-	url := viper.GetString("hub-url")
-
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	userClient, err := c.UserMgr()
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
-	if _, err := userClient.ConfirmEmail(tokenctx,
+	if _, err := userClient.ConfirmEmail(context.Background(),
 		&gwusermgr.ConfirmEmailRequest{Email: c.Args[0],
 			ConfirmationCode: confirmationCode}); err != nil {
 		return err
@@ -515,20 +550,6 @@ func RunUserConfirmEmail(c *CmdConfig) error {
 // RunUserUpdate update user attribute.
 func RunUserUpdate(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
 	updateKey, err := c.Ankr.GetString(c.NS, types.ArgUpdateKeySlug)
 	if err != nil {
 		return err
@@ -566,22 +587,17 @@ func RunUserUpdate(c *CmdConfig) error {
 
 	attributeArray = append(attributeArray, attribute)
 
-	url := viper.GetString("hub-url")
-
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	userClient, err := c.UserMgr()
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
-	rsp, err := userClient.UpdateAttributes(tokenctx,
+	rsp, err := userClient.UpdateAttributes(context.Background(),
 		&gwusermgr.UpdateAttributesRequest{UserAttributes: attributeArray})
 	if err != nil {
 		return err
 	}
 
-	viper.Set("User", rsp)
+	setContextValue(currentContext(), userKey, rsp)
 	if err := writeConfig(); err != nil {
 		return err
 	}
@@ -594,47 +610,28 @@ func RunUserUpdate(c *CmdConfig) error {
 // RunUserDetail get user tail with wallet address.
 func RunUserDetail(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if authResult.AccessToken == "" {
-		return fmt.Errorf("no ankr network access token found")
-	}
-
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	url := viper.GetString("hub-url")
-
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	userClient, err := c.UserMgr()
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return err
 	}
-
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
-	rsp, err := userClient.UserDetail(tokenctx, &common_proto.Empty{})
+	rsp, err := userClient.UserDetail(context.Background(), &common_proto.Empty{})
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Name: %s \n", rsp.Attributes.Name)
-	fmt.Printf("Email: %s \n", rsp.Email)
-	fmt.Printf("Status: %s \n", rsp.Status.String())
-	fmt.Printf("Creation Date: %s \n",
-		time.Unix(int64(rsp.Attributes.CreationDate), 0).Format("Mon Jan 2 15:04:05 MST 2006"))
-	fmt.Printf("Last Modified Date: %s \n",
-		time.Unix(int64(rsp.Attributes.LastModifiedDate), 0).Format("Mon Jan 2 15:04:05 MST 2006"))
-	fmt.Printf("Pubkey: %s \n", rsp.Attributes.PubKey)
-
+	item := &displayers.UserDetail{User: displayers.UserInfo{
+		Name:             rsp.Attributes.Name,
+		Email:            rsp.Email,
+		Status:           rsp.Status.String(),
+		CreationDate:     time.Unix(int64(rsp.Attributes.CreationDate), 0),
+		LastModifiedDate: time.Unix(int64(rsp.Attributes.LastModifiedDate), 0),
+		PubKey:           rsp.Attributes.PubKey,
+		ExtraFields:      map[string]string{},
+	}}
 	for _, a := range rsp.Attributes.ExtraFields {
-		fmt.Printf("%s: %s \n", a.Key, a.Value)
+		item.User.ExtraFields[a.Key] = fmt.Sprint(a.Value)
 	}
 
-	return nil
+	return c.Display(item)
 
 }
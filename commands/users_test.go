@@ -0,0 +1,169 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ankr-network/ankrctl/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// testConfig is a types.Config holding the flags of a test command.
+type testConfig map[string]interface{}
+
+var _ types.Config = testConfig{}
+
+func (c testConfig) Set(ns, key string, val interface{}) { c[key] = val }
+
+func (c testConfig) IsSet(key string) bool {
+	_, ok := c[key]
+	return ok
+}
+
+func (c testConfig) GetString(ns, key string) (string, error) {
+	s, _ := c[key].(string)
+	return s, nil
+}
+
+func (c testConfig) GetBool(ns, key string) (bool, error) {
+	b, _ := c[key].(bool)
+	return b, nil
+}
+
+func (c testConfig) GetInt(ns, key string) (int, error) {
+	i, _ := c[key].(int)
+	return i, nil
+}
+
+func (c testConfig) GetStringSlice(ns, key string) ([]string, error) {
+	s, _ := c[key].([]string)
+	return s, nil
+}
+
+func (c testConfig) GetStringArray(ns, key string) ([]string, error) {
+	s, _ := c[key].([]string)
+	return s, nil
+}
+
+func TestLoginCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ankrctl-login")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	passwordFile := filepath.Join(dir, "password")
+	assert.NoError(t, ioutil.WriteFile(passwordFile, []byte("from-file\n"), 0600))
+	stdinFile := filepath.Join(dir, "stdin")
+	assert.NoError(t, ioutil.WriteFile(stdinFile, []byte("from-stdin\r\n"), 0600))
+
+	tests := []struct {
+		name         string
+		flags        testConfig
+		env          map[string]string
+		stdin        bool
+		wantEmail    string
+		wantPassword string
+		wantErr      string
+	}{
+		{
+			name:         "email flag before ANKR_EMAIL",
+			flags:        testConfig{types.ArgEmailSlug: "flag@ankr.network"},
+			env:          map[string]string{"ANKR_EMAIL": "env@ankr.network", "ANKR_PASSWORD": "from-env"},
+			wantEmail:    "flag@ankr.network",
+			wantPassword: "from-env",
+		},
+		{
+			name:         "ANKR_EMAIL without the flag",
+			env:          map[string]string{"ANKR_EMAIL": "env@ankr.network", "ANKR_PASSWORD": "from-env"},
+			wantEmail:    "env@ankr.network",
+			wantPassword: "from-env",
+		},
+		{
+			name:         "password file before ANKR_PASSWORD",
+			flags:        testConfig{types.ArgEmailSlug: "flag@ankr.network", types.ArgPasswordFileSlug: passwordFile},
+			env:          map[string]string{"ANKR_PASSWORD": "from-env"},
+			wantEmail:    "flag@ankr.network",
+			wantPassword: "from-file",
+		},
+		{
+			name:         "password stdin before ANKR_PASSWORD",
+			flags:        testConfig{types.ArgEmailSlug: "flag@ankr.network", types.ArgPasswordStdinSlug: true},
+			env:          map[string]string{"ANKR_PASSWORD": "from-env"},
+			stdin:        true,
+			wantEmail:    "flag@ankr.network",
+			wantPassword: "from-stdin",
+		},
+		{
+			name:    "password file and stdin together",
+			flags:   testConfig{types.ArgEmailSlug: "flag@ankr.network", types.ArgPasswordFileSlug: passwordFile, types.ArgPasswordStdinSlug: true},
+			wantErr: "cannot be used together",
+		},
+		{
+			name:    "missing password does not prompt without a terminal",
+			flags:   testConfig{types.ArgEmailSlug: "flag@ankr.network"},
+			stdin:   true,
+			wantErr: "no password given",
+		},
+		{
+			name:    "missing email does not prompt without a terminal",
+			env:     map[string]string{"ANKR_PASSWORD": "from-env"},
+			stdin:   true,
+			wantErr: "no email given",
+		},
+		{
+			name:    "missing password file",
+			flags:   testConfig{types.ArgEmailSlug: "flag@ankr.network", types.ArgPasswordFileSlug: filepath.Join(dir, "missing")},
+			wantErr: "unable to read password file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, k := range []string{"ANKR_EMAIL", "ANKR_PASSWORD"} {
+				defer os.Setenv(k, os.Getenv(k))
+				os.Setenv(k, tt.env[k])
+			}
+
+			// a regular file as stdin is never a terminal, so nothing prompts
+			if tt.stdin {
+				f, err := os.Open(stdinFile)
+				assert.NoError(t, err)
+				defer f.Close()
+
+				stdin := os.Stdin
+				defer func() { os.Stdin = stdin }()
+				os.Stdin = f
+			}
+
+			flags := tt.flags
+			if flags == nil {
+				flags = testConfig{}
+			}
+
+			email, password, err := loginCredentials(&CmdConfig{Ankr: flags})
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantEmail, email)
+			assert.Equal(t, tt.wantPassword, password)
+		})
+	}
+}
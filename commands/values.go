@@ -0,0 +1,149 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/Ankr-network/ankrctl/types"
+	"gopkg.in/yaml.v2"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/strvals"
+)
+
+// valuesFlags adds the flags layering chart values to cmd.
+func valuesFlags(cmd *Command) {
+	AddStringArrayFlag(cmd, types.ArgValuesSlug, types.ArgShortValues, []string{},
+		"Values file (yaml), - for stdin. May be repeated, later files win")
+	AddStringArrayFlag(cmd, types.ArgSetSlug, "", []string{},
+		"Set values on top of the files, such as key1=val1,key2.sub=val2 or list={a,b}")
+	AddStringArrayFlag(cmd, types.ArgSetStringSlug, "", []string{},
+		"Set string values on top of the files, such as key1=val1,key2=007")
+	AddStringArrayFlag(cmd, types.ArgSetFileSlug, "", []string{},
+		"Set values to the content of files, such as key1=path1,key2=path2")
+}
+
+// readValues layers the values of c on base: the values files in order,
+// then the --set, --set-string and --set-file values.
+func readValues(c *CmdConfig, base map[string]interface{}) (map[string]interface{}, error) {
+	files, err := c.Ankr.GetStringArray(c.NS, types.ArgValuesSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	sets, err := c.Ankr.GetStringArray(c.NS, types.ArgSetSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	setStrings, err := c.Ankr.GetStringArray(c.NS, types.ArgSetStringSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	setFiles, err := c.Ankr.GetStringArray(c.NS, types.ArgSetFileSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := map[string]interface{}{}
+	mergeValues(vals, base)
+
+	for _, file := range files {
+		fileVals, err := readValuesFile(file)
+		if err != nil {
+			return nil, err
+		}
+		mergeValues(vals, fileVals)
+	}
+
+	for _, set := range sets {
+		if err := strvals.ParseInto(set, vals); err != nil {
+			return nil, fmt.Errorf("invalid --%s %s: %v", types.ArgSetSlug, set, err)
+		}
+	}
+
+	for _, set := range setStrings {
+		if err := strvals.ParseIntoString(set, vals); err != nil {
+			return nil, fmt.Errorf("invalid --%s %s: %v", types.ArgSetStringSlug, set, err)
+		}
+	}
+
+	readFile := func(rs []rune) (interface{}, error) {
+		b, err := ioutil.ReadFile(string(rs))
+		return string(b), err
+	}
+	for _, set := range setFiles {
+		if err := strvals.ParseIntoFile(set, vals, readFile); err != nil {
+			return nil, fmt.Errorf("invalid --%s %s: %v", types.ArgSetFileSlug, set, err)
+		}
+	}
+
+	return vals, nil
+}
+
+// valuesYaml encodes vals as a values.yaml, checking that it reads back.
+func valuesYaml(vals map[string]interface{}) ([]byte, error) {
+	b, err := yaml.Marshal(vals)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := chartutil.ReadValues(b); err != nil {
+		return nil, fmt.Errorf("merged values are not valid yaml: %v", err)
+	}
+
+	return b, nil
+}
+
+func readValuesFile(file string) (map[string]interface{}, error) {
+	var b []byte
+	var err error
+	if file == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	vals, err := chartutil.ReadValues(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse values %s: %v", file, err)
+	}
+
+	return vals, nil
+}
+
+// mergeValues merges src into dst. Maps present in both are merged key by
+// key, any other value of src replaces the one in dst.
+func mergeValues(dst, src map[string]interface{}) map[string]interface{} {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			dst[k] = mergeValues(dstMap, srcMap)
+			continue
+		}
+		if srcIsMap {
+			v = mergeValues(map[string]interface{}{}, srcMap)
+		}
+		dst[k] = v
+	}
+
+	return dst
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/Ankr-network/ankrctl/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeValues(t *testing.T) {
+	dst := map[string]interface{}{
+		"image":    map[string]interface{}{"repository": "nginx", "tag": "1.15"},
+		"replicas": 1,
+	}
+	src := map[string]interface{}{
+		"image":   map[string]interface{}{"tag": "1.17"},
+		"service": map[string]interface{}{"type": "NodePort"},
+	}
+
+	mergeValues(dst, src)
+	assert.Equal(t, map[string]interface{}{
+		"image":    map[string]interface{}{"repository": "nginx", "tag": "1.17"},
+		"replicas": 1,
+		"service":  map[string]interface{}{"type": "NodePort"},
+	}, dst)
+
+	// src maps are copied, not shared
+	src["service"].(map[string]interface{})["type"] = "ClusterIP"
+	assert.Equal(t, "NodePort", dst["service"].(map[string]interface{})["type"])
+}
+
+func TestReadValuesFlags(t *testing.T) {
+	defer viper.Reset()
+
+	parent := &Command{Command: &cobra.Command{Use: "chart"}}
+	cmd := CmdBuilder(parent, func(c *CmdConfig) error { return nil }, "template", "", nil)
+	valuesFlags(cmd)
+
+	err := cmd.Flags().Parse([]string{
+		"--set", "servers={a,b}", "--set", "image.tag=1.17,replicas=2",
+		"--set-string", "version=007",
+	})
+	assert.NoError(t, err)
+
+	c := &CmdConfig{NS: cmdNS(cmd.Command), Ankr: &types.LiveConfig{}}
+	vals, err := readValues(c, map[string]interface{}{"replicas": 1, "service": "ClusterIP"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"servers":  []interface{}{"a", "b"},
+		"image":    map[string]interface{}{"tag": "1.17"},
+		"replicas": int64(2),
+		"service":  "ClusterIP",
+		"version":  "007",
+	}, vals)
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Ankr-network/ankrctl/types"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	gwtaskmgr "github.com/Ankr-network/dccn-common/protos/gateway/taskmgr/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultWaitTimeout bounds how long --wait and 'app wait' wait.
+	defaultWaitTimeout = "5m"
+
+	// waitMinInterval and waitMaxInterval bound the backoff between polls.
+	waitMinInterval = time.Second
+	waitMaxInterval = 10 * time.Second
+)
+
+// appFailedStatuses are the statuses the hub gives an app whose deployment,
+// update, cancel or purge failed.
+var appFailedStatuses = map[string]bool{
+	"failed": true, "update_failed": true, "cancel_failed": true, "purge_failed": true,
+}
+
+// appCondition is the state an app is waited for.
+type appCondition struct {
+	// status is the wanted status, such as running, unless deleted is set
+	status  string
+	deleted bool
+}
+
+func (c appCondition) String() string {
+	if c.deleted {
+		return "deleted"
+	}
+
+	return c.status
+}
+
+// parseAppCondition parses a --for value, either status=<status> or delete.
+func parseAppCondition(s string) (appCondition, error) {
+	if s == "delete" {
+		return appCondition{deleted: true}, nil
+	}
+
+	if strings.HasPrefix(s, "status=") {
+		if st := normalizeAppStatus(strings.TrimPrefix(s, "status=")); st != "" {
+			return appCondition{status: st}, nil
+		}
+	}
+
+	return appCondition{}, fmt.Errorf("invalid condition %q, use status=<status> or delete", s)
+}
+
+// normalizeAppStatus turns a status such as APP_RUNNING into running.
+func normalizeAppStatus(s string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "app_")
+}
+
+// appStatusName returns the status of an app as 'app wait' matches it.
+func appStatusName(r *common_proto.AppReport) string {
+	return normalizeAppStatus(r.AppStatus.String())
+}
+
+// appEventName returns the last event of an app in lower case.
+func appEventName(r *common_proto.AppReport) string {
+	return strings.ToLower(r.AppEvent.String())
+}
+
+// waitFlags adds --wait and --timeout to a command.
+func waitFlags(cmd *Command, desc string) {
+	AddBoolFlag(cmd, types.ArgWaitSlug, "", false, desc)
+	AddStringFlag(cmd, types.ArgTimeoutSlug, "", defaultWaitTimeout, "How long to wait, such as 30s or 10m")
+}
+
+// waitOptions returns the values of --wait and --timeout.
+func waitOptions(c *CmdConfig) (bool, time.Duration, error) {
+	wait, err := c.Ankr.GetBool(c.NS, types.ArgWaitSlug)
+	if err != nil {
+		return false, 0, err
+	}
+
+	timeout, err := waitTimeout(c)
+	return wait, timeout, err
+}
+
+func waitTimeout(c *CmdConfig) (time.Duration, error) {
+	s, err := c.Ankr.GetString(c.NS, types.ArgTimeoutSlug)
+	if err != nil {
+		return 0, err
+	}
+
+	timeout, err := time.ParseDuration(s)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q, use a duration such as 30s or 10m", s)
+	}
+
+	return timeout, nil
+}
+
+// waitForApp polls an app with backoff until it meets cond, printing every
+// status and event transition to log. It fails when the app reaches a failed
+// status it is not waited for, or when ctx is done.
+func waitForApp(ctx context.Context, appClient gwtaskmgr.AppMgrClient, appID string, cond appCondition, log io.Writer) error {
+	interval := waitMinInterval
+	last, lastStatus := "", "unknown"
+
+	for {
+		callCtx, cancel := context.WithTimeout(ctx, defaultHubTimeout)
+		rsp, err := appClient.AppDetail(callCtx, &gwtaskmgr.AppID{AppId: appID})
+		cancel()
+
+		switch {
+		case err == nil && rsp.AppReport != nil:
+			st, ev := appStatusName(rsp.AppReport), appEventName(rsp.AppReport)
+			if st+ev != last {
+				fmt.Fprintf(log, "%s  %s  %s (%s)\n", time.Now().Format("15:04:05"), appID, st, ev)
+				last, lastStatus = st+ev, st
+			}
+
+			if cond.deleted && (st == "purged" || st == "deleted") {
+				return nil
+			}
+			if !cond.deleted && st == cond.status {
+				return nil
+			}
+			if appFailedStatuses[st] {
+				return fmt.Errorf("app %s is %s (%s)", appID, st, ev)
+			}
+		case err == nil:
+		case status.Code(err) == codes.NotFound:
+			if cond.deleted {
+				fmt.Fprintf(log, "%s  %s  deleted\n", time.Now().Format("15:04:05"), appID)
+				return nil
+			}
+			return fmt.Errorf("app %s not found", appID)
+		case status.Code(err) == codes.Unavailable, status.Code(err) == codes.DeadlineExceeded:
+			// the hub may be briefly unreachable while the app changes
+		default:
+			return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for app %s to be %s, last status: %s", appID, cond, lastStatus)
+		case <-time.After(interval):
+		}
+
+		interval = interval * 3 / 2
+		if interval > waitMaxInterval {
+			interval = waitMaxInterval
+		}
+	}
+}
+
+// waitForApps waits until every app in ids meets cond, sharing one timeout.
+func waitForApps(appClient gwtaskmgr.AppMgrClient, ids []string, cond appCondition, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, id := range ids {
+		if err := waitForApp(ctx, appClient, id, cond, os.Stderr); err != nil {
+			return err
+		}
+		fmt.Printf("App %s is %s.\n", id, cond)
+	}
+
+	return nil
+}
+
+// RunAppWait waits until apps meet a condition.
+func RunAppWait(c *CmdConfig) error {
+
+	if len(c.Args) < 1 {
+		return types.NewMissingArgsErr(c.NS)
+	}
+
+	forCond, err := c.Ankr.GetString(c.NS, types.ArgForSlug)
+	if err != nil {
+		return err
+	}
+
+	cond, err := parseAppCondition(forCond)
+	if err != nil {
+		return err
+	}
+
+	timeout, err := waitTimeout(c)
+	if err != nil {
+		return err
+	}
+
+	appClient, err := c.AppMgr()
+	if err != nil {
+		return err
+	}
+
+	return waitForApps(appClient, c.Args, cond, timeout)
+}
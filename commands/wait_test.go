@@ -0,0 +1,106 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseAppCondition(t *testing.T) {
+	tests := []struct {
+		in   string
+		want appCondition
+		err  bool
+	}{
+		{in: "status=running", want: appCondition{status: "running"}},
+		{in: "status=APP_RUNNING", want: appCondition{status: "running"}},
+		{in: "status=Canceled", want: appCondition{status: "canceled"}},
+		{in: "delete", want: appCondition{deleted: true}},
+		{in: "status=", err: true},
+		{in: "running", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAppCondition(tt.in)
+		if tt.err {
+			assert.Error(t, err, tt.in)
+			continue
+		}
+		if assert.NoError(t, err, tt.in) {
+			assert.Equal(t, tt.want, got, tt.in)
+		}
+	}
+}
+
+func TestAppFailedStatuses(t *testing.T) {
+	for _, st := range []string{"failed", "update_failed", "cancel_failed", "purge_failed"} {
+		assert.True(t, appFailedStatuses[st], st)
+	}
+	// only the hub's failed statuses fail a wait, not any status naming a failure
+	for _, st := range []string{"running", "canceled", "purged", "failover", "launch_app_failed"} {
+		assert.False(t, appFailedStatuses[st], st)
+	}
+}
+
+func TestWaitForApp(t *testing.T) {
+	app := testApp("app-1", "web", "ns-1")
+	// the status of app, whatever the hub names it
+	current := appCondition{status: appStatusName(app)}
+
+	tests := []struct {
+		name   string
+		appMgr *fakeAppMgr
+		cond   appCondition
+		err    string
+	}{
+		{name: "condition met", appMgr: &fakeAppMgr{apps: []*common_proto.AppReport{app}}, cond: current},
+		{name: "deleted", appMgr: &fakeAppMgr{}, cond: appCondition{deleted: true}},
+		{name: "not found", appMgr: &fakeAppMgr{}, cond: current, err: "app app-1 not found"},
+		{
+			name:   "hub error",
+			appMgr: &fakeAppMgr{detailErr: status.Error(codes.PermissionDenied, "denied")},
+			cond:   current, err: "Status Code: PermissionDenied",
+		},
+		{
+			name:   "timeout",
+			appMgr: &fakeAppMgr{detailErr: status.Error(codes.Unavailable, "hub down")},
+			cond:   current, err: "timed out waiting for app app-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			var log bytes.Buffer
+			err := waitForApp(ctx, tt.appMgr, "app-1", tt.cond, &log)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.err)
+			}
+		})
+	}
+}
@@ -29,16 +29,13 @@ import (
 
 	"github.com/Ankr-network/ankrctl/types"
 	"github.com/Ankr-network/ankrctl/commands/displayers"
-	ankr_const "github.com/Ankr-network/dccn-common"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	gwusermgr "github.com/Ankr-network/dccn-common/protos/gateway/usermgr/v1"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"golang.org/x/crypto/ssh/terminal"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"github.com/Ankr-network/ankr-chain/common"
 	"github.com/Ankr-network/ankr-chain-sdk-go/rpc/query"
 	"github.com/Ankr-network/ankr-chain-sdk-go/rpc/wallet"
@@ -71,9 +68,9 @@ func walletCmd() *Command {
 	_ = cmdWalletGenkey
 
 	//DCCN-CLI wallet keylist
-	cmdWalletKeylist := CmdBuilder(cmd, RunWalletKeylist, "listkey", "list key pair for Mainnet",
-		Writer, aliasOpt("kl"), docCategories("wallet"))
-	_ = cmdWalletKeylist
+	cmdWalletKeylist := CmdBuilder(cmd, RunWalletKeylist, "listkey [GLOB]", "list key pair for Mainnet",
+		Writer, aliasOpt("kl"), displayerType(&displayers.Key{}), docCategories("wallet"))
+	filterFlags(cmdWalletKeylist)
 
 	//DCCN-CLI wallet importkey
 	cmdWalletImportkey := CmdBuilder(cmd, RunWalletImportkey, "importkey <keyname>",
@@ -97,7 +94,8 @@ func walletCmd() *Command {
 
 	//DCCN-CLI wallet get balance
 	cmdWalletGetBalance := CmdBuilder(cmd, RunWalletGetBalance, "getbalance <address>",
-		"get balance of wallet by address", Writer, aliasOpt("gb"), docCategories("wallet"))
+		"get balance of wallet by address", Writer, aliasOpt("gb"), displayerType(&displayers.Balance{}),
+		docCategories("wallet"))
 	_ = cmdWalletGetBalance
 
 	cmdWalletGetAccount := CmdBuilder(cmd, RunWalletGetAccount, "getaccount <address>",
@@ -112,13 +110,15 @@ func walletCmd() *Command {
 
 	//DCCN-CLI wallet search deposit in a period
 	cmdWalletSearchDeposit := CmdBuilder(cmd, RunWalletSearchDeposit, "search",
-		"wallet search deposit in a period", Writer, aliasOpt("sd"), docCategories("wallet"))
+		"wallet search deposit in a period", Writer, aliasOpt("sd"), displayerType(&displayers.Deposit{}),
+		docCategories("wallet"))
 	AddStringFlag(cmdWalletSearchDeposit, types.ArgSearchDepositStartSlug, "", "", "wallet search deposit start date (format: `mm/dd/yyyy`)", requiredOpt())
 	AddStringFlag(cmdWalletSearchDeposit, types.ArgSearchDepositEndSlug, "", "", "wallet address deposit end date (format: `mm/dd/yyyy`)", requiredOpt())
 
 	//DCCN-CLI wallet get deposit history
 	cmdWalletDepositHistory := CmdBuilder(cmd, RunWalletDepositHistory, "history",
-		"retrieve wallet deposit history", Writer, aliasOpt("dh"), docCategories("wallet"))
+		"retrieve wallet deposit history", Writer, aliasOpt("dh"), displayerType(&displayers.Deposit{}),
+		docCategories("wallet"))
 	_ = cmdWalletDepositHistory
 
 	return cmd
@@ -281,8 +281,9 @@ func RunWalletKeylist(c *CmdConfig) error {
 			})
 		}
 	}
-	item := &displayers.Key{Keystores: keylist}
-	return c.Display(item)
+	return c.DisplayList(func() (displayers.Displayable, error) {
+		return &displayers.Key{Keystores: keylist}, nil
+	})
 }
 
 // RunWalletImportkey import wallet key.
@@ -587,7 +588,7 @@ func RunWalletGetBalance(c *CmdConfig) error {
 	}
 	address := c.Args[0]
 
-	fmt.Printf("\nquerying balance of address: %s\n", address)
+	fmt.Fprintf(os.Stderr, "\nquerying balance of address: %s\n", address)
 	if tendermintURL == "" {
 		tendermintURL = "https://chain-01.dccn.ankr.com;https://chain-02.dccn.ankr.com;https://chain-03.dccn.ankr.com"
 	}
@@ -609,12 +610,13 @@ func RunWalletGetBalance(c *CmdConfig) error {
 	cl := query.NewQueryClient(tendermintURL+":"+tendermintPort)
 	balAmount, err := cl.GetBalance(address, "ANKR")
 	if err != nil {
-		fmt.Println("Query balance error:", err)
-		return err
+		return fmt.Errorf("query balance error: %v", err)
 	}
-	fmt.Printf("wallet balance: %s\n", balAmount)
 
-	return nil
+	item := &displayers.Balance{Balances: []displayers.BalanceInfo{
+		{Address: address, Symbol: "ANKR", Amount: fmt.Sprint(balAmount)},
+	}}
+	return c.Display(item)
 }
 
 // RunWalletGetAccount get balance from chain.
@@ -681,33 +683,12 @@ func RunWalletGenAddress(c *CmdConfig) error {
 		return nil
 	}
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if len(authResult.AccessToken) == 0 {
-		fmt.Fprintf(os.Stderr, "\nERROR: %s\n", "no ankr network access token found")
-		return nil
-	}
-
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	url := viper.GetString("hub-url")
-
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	userClient, err := c.UserMgr()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "\nERROR: did not connect, %v \n", err)
-		return nil
+		return err
 	}
 
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
-
-	rsp, err := userClient.CreateAddress(tokenctx,
+	rsp, err := userClient.CreateAddress(context.Background(),
 		&gwusermgr.GenerateAddressRequest{
 			Type:    addressType,
 			Purpose: addressPurpose,
@@ -732,8 +713,7 @@ func RunWalletSearchDeposit(c *CmdConfig) error {
 	}
 	startTime, err := time.Parse("01/02/2006", start)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "\nERROR: %s\n", err.Error())
-		return nil
+		return fmt.Errorf("invalid start date %q, use MM/DD/YYYY", start)
 	}
 	end, err := c.Ankr.GetString(c.NS, types.ArgSearchDepositEndSlug)
 	if err != nil {
@@ -741,37 +721,15 @@ func RunWalletSearchDeposit(c *CmdConfig) error {
 	}
 	endTime, err := time.Parse("01/02/2006", end)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "\nERROR: %s\n", err.Error())
-		return nil
-	}
-
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if len(authResult.AccessToken) == 0 {
-		fmt.Fprintf(os.Stderr, "\nERROR: %s\n", "no ankr network access token found")
-		return nil
+		return fmt.Errorf("invalid end date %q, use MM/DD/YYYY", end)
 	}
 
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	url := viper.GetString("hub-url")
-
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	userClient, err := c.UserMgr()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "\nERROR: did not connect, %v\n", err)
-		return nil
+		return err
 	}
 
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
-
-	rsp, err := userClient.SearchDeposit(tokenctx,
+	rsp, err := userClient.SearchDeposit(context.Background(),
 		&gwusermgr.SearchDepositRequest{
 			Start: &timestamp.Timestamp{
 				Seconds: startTime.Unix(),
@@ -781,69 +739,61 @@ func RunWalletSearchDeposit(c *CmdConfig) error {
 			},
 		})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "\nERROR: %s\n", err.Error())
-		return nil
+		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 	}
 
-	for _, v := range rsp.Deposits {
-		amount, ok := new(big.Float).SetString(v.Amount)
-		if !ok {
-			fmt.Fprintf(os.Stderr, "\nERROR: can not parse amount '%s'\n", v.Amount)
-			return nil
-		}
-		fmt.Printf("Time: %s\nHash: %s\nState: %s\nConfirmed Block Height: %s\nFrom Account Address Type: %s\nFrom Account Address: %s\nTo Account Address Type: %s\nTo Account Address: %s\nAmount: %v\n\n",
-			ptypes.TimestampString(v.Time), v.TxHash, v.TxState, v.ConfirmedBlockHeight, v.FromAccountAddressType, v.FromAccountAddress, v.ToAccountAddressType, v.ToAccountAddress, new(big.Float).Quo(amount, big.NewFloat(float64(1000000000000000000.0))).String())
+	item, err := depositList(rsp.Deposits)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return c.Display(item)
 }
 
 // RunWalletDepositHistory return deposit history for certain period.
 func RunWalletDepositHistory(c *CmdConfig) error {
 
-	authResult := gwusermgr.AuthenticationResult{}
-	viper.UnmarshalKey("AuthResult", &authResult)
-
-	if len(authResult.AccessToken) == 0 {
-		fmt.Fprintf(os.Stderr, "\nERROR: %s\n", "no ankr network access token found")
-		return nil
+	userClient, err := c.UserMgr()
+	if err != nil {
+		return err
 	}
 
-	md := metadata.New(map[string]string{
-		"token": authResult.AccessToken,
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-	tokenctx, cancel := context.WithTimeout(ctx, ankr_const.ClientTimeOut*time.Second)
-	defer cancel()
-
-	url := viper.GetString("hub-url")
-
-	conn, err := grpc.Dial(url+port, grpc.WithInsecure())
+	rsp, err := userClient.DepositHistory(context.Background(), &common_proto.Empty{})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "\nERROR: did not connect: %v\n", err)
-		return nil
+		return fmt.Errorf("Status Code: %s  Message: %s", status.Code(err), err.Error())
 	}
 
-	defer conn.Close()
-	userClient := gwusermgr.NewUserMgrClient(conn)
-
-	rsp, err := userClient.DepositHistory(tokenctx, &common_proto.Empty{})
+	item, err := depositList(rsp.Deposits)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "\nERROR: %s\n", err.Error())
-		return nil
+		return err
 	}
 
-	for _, v := range rsp.Deposits {
+	return c.Display(item)
+}
+
+// depositList converts the deposits returned by the hub for display. Their
+// amounts are in the smallest unit, 1e-18 ANKR.
+func depositList(deposits []*gwusermgr.Deposit) (*displayers.Deposit, error) {
+	item := &displayers.Deposit{}
+	for _, v := range deposits {
 		amount, ok := new(big.Float).SetString(v.Amount)
 		if !ok {
-			fmt.Fprintf(os.Stderr, "\nERROR: can not parse amount '%s'\n", v.Amount)
-			return nil
+			return nil, fmt.Errorf("can not parse amount '%s'", v.Amount)
 		}
-		fmt.Fprintf(os.Stderr, "Time: %s\nHash: %s\nState: %s\nConfirmed Block Height: %s\nFrom Account Address Type: %s\nFrom Account Address: %s\nTo Account Address Type: %s\nTo Account Address: %s\nAmount: %v\n\n",
-			ptypes.TimestampString(v.Time), v.TxHash, v.TxState, v.ConfirmedBlockHeight, v.FromAccountAddressType, v.FromAccountAddress, v.ToAccountAddressType, v.ToAccountAddress, new(big.Float).Quo(amount, big.NewFloat(float64(1000000000000000000.0))).String())
+		item.Deposits = append(item.Deposits, displayers.DepositInfo{
+			Time:                   ptypes.TimestampString(v.Time),
+			TxHash:                 v.TxHash,
+			TxState:                fmt.Sprint(v.TxState),
+			ConfirmedBlockHeight:   fmt.Sprint(v.ConfirmedBlockHeight),
+			FromAccountAddressType: fmt.Sprint(v.FromAccountAddressType),
+			FromAccountAddress:     v.FromAccountAddress,
+			ToAccountAddressType:   fmt.Sprint(v.ToAccountAddressType),
+			ToAccountAddress:       v.ToAccountAddress,
+			Amount:                 new(big.Float).Quo(amount, big.NewFloat(float64(1000000000000000000.0))).String(),
+		})
 	}
 
-	return nil
+	return item, nil
 }
 
 func Shuffle(slice []string) []string {
@@ -0,0 +1,96 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/Ankr-network/ankrctl/commands/displayers"
+	"github.com/Ankr-network/ankrctl/types"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	gwusermgr "github.com/Ankr-network/dccn-common/protos/gateway/usermgr/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeUserMgr fails the deposit calls of the user manager with err, the
+// other calls panic.
+type fakeUserMgr struct {
+	gwusermgr.UserMgrClient
+	err error
+}
+
+func (f *fakeUserMgr) SearchDeposit(ctx context.Context, in *gwusermgr.SearchDepositRequest, opts ...grpc.CallOption) (*gwusermgr.SearchDepositResponse, error) {
+	return nil, f.err
+}
+
+func (f *fakeUserMgr) DepositHistory(ctx context.Context, in *common_proto.Empty, opts ...grpc.CallOption) (*gwusermgr.DepositHistoryResponse, error) {
+	return nil, f.err
+}
+
+func TestRunWalletDepositErrors(t *testing.T) {
+	userMgr := &fakeUserMgr{err: status.Error(codes.Unauthenticated, "token expired")}
+	config := func(start, end string) *CmdConfig {
+		return &CmdConfig{
+			NS:      "wallet",
+			Ankr:    testConfig{types.ArgSearchDepositStartSlug: start, types.ArgSearchDepositEndSlug: end},
+			Out:     &bytes.Buffer{},
+			UserMgr: func() (gwusermgr.UserMgrClient, error) { return userMgr, nil },
+		}
+	}
+
+	err := RunWalletDepositHistory(config("", ""))
+	assert.EqualError(t, err, "Status Code: Unauthenticated  Message: rpc error: code = Unauthenticated desc = token expired")
+
+	err = RunWalletSearchDeposit(config("06/01/2019", "06/30/2019"))
+	assert.EqualError(t, err, "Status Code: Unauthenticated  Message: rpc error: code = Unauthenticated desc = token expired")
+
+	err = RunWalletSearchDeposit(config("2019-06-01", "06/30/2019"))
+	assert.EqualError(t, err, `invalid start date "2019-06-01", use MM/DD/YYYY`)
+}
+
+func TestDepositList(t *testing.T) {
+	item, err := depositList([]*gwusermgr.Deposit{
+		{
+			Time:               &timestamp.Timestamp{Seconds: 1559487600},
+			TxHash:             "0xabc",
+			FromAccountAddress: "0x1",
+			ToAccountAddress:   "0x2",
+			Amount:             "1500000000000000000",
+		},
+		{Time: &timestamp.Timestamp{}, Amount: "250000000000000"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, item.Deposits, 2)
+
+	d := item.Deposits[0]
+	assert.Equal(t, "2019-06-02T15:00:00Z", d.Time)
+	assert.Equal(t, "0xabc", d.TxHash)
+	assert.Equal(t, "0x1", d.FromAccountAddress)
+	assert.Equal(t, "0x2", d.ToAccountAddress)
+	assert.Equal(t, "1.5", d.Amount)
+	assert.Equal(t, "0.00025", item.Deposits[1].Amount)
+
+	item, err = depositList(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &displayers.Deposit{}, item)
+
+	_, err = depositList([]*gwusermgr.Deposit{{Amount: "1.5 ANKR"}})
+	assert.EqualError(t, err, "can not parse amount '1.5 ANKR'")
+}
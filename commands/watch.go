@@ -0,0 +1,114 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/Ankr-network/ankrctl/commands/displayers"
+	"github.com/Ankr-network/ankrctl/types"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// defaultWatchInterval is how often --watch refreshes a list.
+const defaultWatchInterval = "2s"
+
+// watchFlags adds --watch and --interval to a list command.
+func watchFlags(cmd *Command) {
+	AddBoolFlag(cmd, types.ArgWatchSlug, types.ArgShortWatch, false, "Keep refreshing the list until interrupted")
+	AddStringFlag(cmd, types.ArgIntervalSlug, "", defaultWatchInterval, "How often to refresh with --watch, such as 5s")
+}
+
+// DisplayList displays the list returned by fetch, keeping the rows that
+// match the GLOB arguments and --field-selector. With --watch it polls fetch
+// every --interval and shows the changes until interrupted.
+func (c *CmdConfig) DisplayList(fetch func() (displayers.Displayable, error)) error {
+	filter, err := newListFilter(c)
+	if err != nil {
+		return err
+	}
+
+	list := func() (displayers.Displayable, error) {
+		item, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+
+		return filter.apply(item)
+	}
+
+	item, err := list()
+	if err != nil {
+		return err
+	}
+
+	watch, err := c.Ankr.GetBool(c.NS, types.ArgWatchSlug)
+	if err != nil || !watch {
+		return c.Display(item)
+	}
+
+	s, err := c.Ankr.GetString(c.NS, types.ArgIntervalSlug)
+	if err != nil {
+		return err
+	}
+
+	interval, err := time.ParseDuration(s)
+	if err != nil || interval <= 0 {
+		return fmt.Errorf("invalid interval %q, use a duration such as 5s", s)
+	}
+
+	wd := &displayers.WatchDisplayer{
+		Displayer: displayers.Displayer{
+			NS:     c.NS,
+			Config: c.Ankr,
+			Out:    c.Out,
+		},
+		Interval: interval,
+		TTY:      isTerminal(c.Out),
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err != nil {
+			// keep watching, the hub may be briefly unavailable
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		} else if err := wd.Update(item); err != nil {
+			return err
+		}
+
+		select {
+		case <-interrupt:
+			return nil
+		case <-ticker.C:
+		}
+
+		item, err = list()
+	}
+}
+
+// isTerminal reports whether w writes to a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && terminal.IsTerminal(int(f.Fd()))
+}
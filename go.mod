@@ -8,12 +8,17 @@ require (
 	github.com/Ankr-network/ankr-chain v1.0.2
 	github.com/Ankr-network/ankr-chain-sdk-go v0.0.0-20191210085204-77c9c68524c6
 	github.com/Ankr-network/dccn-common v0.0.0-20191031140944-a011058c93dd
-	github.com/Masterminds/semver v1.4.2 // indirect
+	github.com/Masterminds/goutils v1.1.0 // indirect
+	github.com/Masterminds/semver v1.4.2
+	github.com/Masterminds/sprig v2.20.0+incompatible
 	github.com/cyphar/filepath-securejoin v0.2.2 // indirect
 	github.com/fatih/color v1.7.0
 	github.com/gobwas/glob v0.2.3
 	github.com/golang/protobuf v1.3.2
+	github.com/google/uuid v1.1.1 // indirect
 	github.com/grpc-ecosystem/grpc-gateway v1.11.4-0.20191029091745-69669120b0e0 // indirect
+	github.com/huandu/xstrings v1.2.0 // indirect
+	github.com/imdario/mergo v0.3.8 // indirect
 	github.com/shiena/ansicolor v0.0.0-20151119151921-a422bbe96644
 	github.com/spf13/cobra v0.0.5
 	github.com/spf13/jwalterweatherman v1.1.0
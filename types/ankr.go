@@ -15,6 +15,7 @@ package types
 
 import (
 	"bytes"
+	"encoding/csv"
 	"fmt"
 	"os"
 	"regexp"
@@ -85,6 +86,7 @@ type Config interface {
 	GetBool(ns, key string) (bool, error)
 	GetInt(ns, key string) (int, error)
 	GetStringSlice(ns, key string) ([]string, error)
+	GetStringArray(ns, key string) ([]string, error)
 }
 
 // LiveConfig is an implementation of Config for live values.
@@ -197,6 +199,36 @@ func (c *LiveConfig) GetStringSlice(ns, key string) ([]string, error) {
 	return out, nil
 }
 
+// GetStringArray returns a config value as a string array. Unlike
+// GetStringSlice, its items are never split on commas.
+func (c *LiveConfig) GetStringArray(ns, key string) ([]string, error) {
+	nskey := key
+	if ns != NSRoot {
+		nskey = fmt.Sprintf("%s.%s", ns, key)
+	}
+
+	var out []string
+	if s, ok := viper.Get(nskey).(string); ok {
+		// a StringArray flag reads back as its value, such as [a,"b,c"]
+		s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+		if s != "" {
+			var err error
+			if out, err = csv.NewReader(strings.NewReader(s)).Read(); err != nil {
+				return nil, fmt.Errorf("unable to read %s: %v", nskey, err)
+			}
+		}
+	} else {
+		out = viper.GetStringSlice(nskey)
+	}
+
+	isRequired := viper.GetBool(fmt.Sprintf("required.%s", nskey))
+	if isRequired && emptyStringSlice(out) {
+		return nil, NewMissingArgsErr(nskey)
+	}
+
+	return out, nil
+}
+
 // This is needed because an empty StringSlice flag returns `["[]"]`
 func emptyStringSlice(s []string) bool {
 	return len(s) == 1 && s[0] == "[]"
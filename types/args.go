@@ -18,6 +18,12 @@ const (
 	ArgAccessToken = "access-token"
 	// ArgContext is the name of the auth context to use
 	ArgContext = "context"
+	// ArgHubURL is the address of the hub to connect to.
+	ArgHubURL = "hub-url"
+	// ArgVerbose toggles verbose output.
+	ArgVerbose = "verbose"
+	// ArgTrace toggles tracing of hub calls.
+	ArgTrace = "trace"
 	// ArgUserID is a user id argument.
 	ArgUserID = "userid"
 	// ArgClusterNameSlug is a datacenter slug argument.
@@ -132,4 +138,58 @@ const (
 	ArgUpdateValueSlug = "update-value"
 	// ArgEmailCodeSlug is a confirm email change slug argument.
 	ArgEmailCodeSlug = "email-code"
+	// ArgShowExpirySlug shows when the access token expires.
+	ArgShowExpirySlug = "show-expiry"
+	// ArgPasswordStdinSlug reads the password from stdin.
+	ArgPasswordStdinSlug = "password-stdin"
+	// ArgPasswordFileSlug reads the password from a file.
+	ArgPasswordFileSlug = "password-file"
+	// ArgFileSlug is a manifest file argument.
+	ArgFileSlug = "file"
+	// ArgDryRunSlug shows what a command would change without changing it.
+	ArgDryRunSlug = "dry-run"
+	// ArgWaitSlug waits until an app reaches its target status.
+	ArgWaitSlug = "wait"
+	// ArgTimeoutSlug is how long to wait.
+	ArgTimeoutSlug = "timeout"
+	// ArgForSlug is the condition to wait for.
+	ArgForSlug = "for"
+	// ArgWatchSlug keeps refreshing a list.
+	ArgWatchSlug = "watch"
+	// ArgIntervalSlug is how often a watched list is refreshed.
+	ArgIntervalSlug = "interval"
+	// ArgFieldSelectorSlug selects the rows of a list by field.
+	ArgFieldSelectorSlug = "field-selector"
+	// ArgSortBySlug is the column a table is sorted by.
+	ArgSortBySlug = "sort-by"
+	// ArgNearSlug is the location clusters are ranked by distance from.
+	ArgNearSlug = "near"
+	// ArgNameContainsSlug limits recommended clusters to those whose name contains it.
+	ArgNameContainsSlug = "name-contains"
+	// ArgConcurrencySlug is how many items a batch command processes at once.
+	ArgConcurrencySlug = "concurrency"
+	// ArgContinueOnErrorSlug keeps a batch command going after an item fails.
+	ArgContinueOnErrorSlug = "continue-on-error"
+	// ArgRepoSlug is the repo of a chart.
+	ArgRepoSlug = "repo"
+	// ArgVersionSlug is the version of a chart.
+	ArgVersionSlug = "version"
+	// ArgValuesSlug is a chart values file.
+	ArgValuesSlug = "values"
+	// ArgSetSlug sets a chart value on the command line.
+	ArgSetSlug = "set"
+	// ArgSetStringSlug sets a chart value as a string on the command line.
+	ArgSetStringSlug = "set-string"
+	// ArgSetFileSlug sets a chart value to the content of a file.
+	ArgSetFileSlug = "set-file"
+	// ArgBaseValuesSlug starts from the values of the source chart.
+	ArgBaseValuesSlug = "base-values"
+	// ArgFromSlug is the repo@version a chart diff starts from.
+	ArgFromSlug = "from"
+	// ArgToSlug is the repo@version a chart diff goes to.
+	ArgToSlug = "to"
+	// ArgVersionsSlug lists every version of the charts.
+	ArgVersionsSlug = "versions"
+	// ArgOutputDirSlug is the directory rendered manifests are written to.
+	ArgOutputDirSlug = "output-dir"
 )
@@ -16,4 +16,14 @@ package types
 const (
 	// ArgShortForce forces confirmation on actions
 	ArgShortForce = "f"
+	// ArgShortFile is the manifest file argument.
+	ArgShortFile = "f"
+	// ArgShortOutput is the output type argument.
+	ArgShortOutput = "o"
+	// ArgShortVerbose toggles verbose output.
+	ArgShortVerbose = "v"
+	// ArgShortWatch keeps refreshing a list.
+	ArgShortWatch = "w"
+	// ArgShortValues is a chart values file.
+	ArgShortValues = "f"
 )
@@ -0,0 +1,103 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+// The hub measures CPU in mCPUs and memory and storage in MiB.
+const (
+	MilliCPUPerCPU = 1000
+	MiBPerGiB      = 1024
+)
+
+// quantityRegexp splits a quantity such as 1.5Gi into its number and unit.
+var quantityRegexp = regexp.MustCompile(`^([+-]?(?:[0-9]+\.?[0-9]*|\.[0-9]+))([a-zA-Z]*)$`)
+
+// cpuUnits scales CPU quantities to mCPUs. A bare number is in vCPUs.
+var cpuUnits = map[string]float64{
+	"":  MilliCPUPerCPU,
+	"m": 1,
+}
+
+// sizeUnits scales memory and storage quantities to MiB. A bare number is
+// in GiB, binary units end in i and decimal units are powers of 1000.
+var sizeUnits = map[string]float64{
+	"":   MiBPerGiB,
+	"Ki": 1.0 / 1024,
+	"Mi": 1,
+	"Gi": 1 << 10,
+	"Ti": 1 << 20,
+	"k":  1e3 / (1 << 20),
+	"K":  1e3 / (1 << 20),
+	"M":  1e6 / (1 << 20),
+	"G":  1e9 / (1 << 20),
+	"T":  1e12 / (1 << 20),
+}
+
+// ParseCPU converts a CPU quantity, in vCPUs such as 2 or 0.5 or in mCPUs
+// such as 500m, to the mCPUs used by the hub.
+func ParseCPU(s string) (uint32, error) {
+	return parseQuantity(s, cpuUnits, "m", "vCPUs such as 2 or 0.5, or mCPUs such as 500m")
+}
+
+// ParseSize converts a memory or storage quantity, in GiB such as 2 or with
+// a unit such as 512Mi, 1.5Gi or 20G, to the MiB used by the hub.
+func ParseSize(s string) (uint32, error) {
+	return parseQuantity(s, sizeUnits, "Mi", "GiB such as 2, or a size such as 512Mi, 1.5Gi or 20G")
+}
+
+func parseQuantity(s string, units map[string]float64, base, hint string) (uint32, error) {
+	m := quantityRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("invalid quantity %q, use %s", s, hint)
+	}
+
+	unit, ok := units[m[2]]
+	if !ok {
+		return 0, fmt.Errorf("invalid unit %q in %q, use %s", m[2], s, hint)
+	}
+
+	f, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q, use %s", s, hint)
+	}
+	if f <= 0 {
+		return 0, fmt.Errorf("quantity %q must be greater than zero", s)
+	}
+
+	v := math.Round(f * unit)
+	if v < 1 {
+		return 0, fmt.Errorf("quantity %q is less than 1%s", s, base)
+	}
+	if v > math.MaxUint32 {
+		return 0, fmt.Errorf("quantity %q is more than the maximum of %d%s", s, uint32(math.MaxUint32), base)
+	}
+
+	return uint32(v), nil
+}
+
+// FormatCPU formats an amount of vCPUs for display.
+func FormatCPU(vcpus float64) string {
+	return fmt.Sprintf("%.2f vCPU(s)", vcpus)
+}
+
+// FormatSize formats an amount of memory or storage in GiB for display.
+func FormatSize(gib float64) string {
+	return fmt.Sprintf("%.2fGiB", gib)
+}
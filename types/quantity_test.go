@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Dccncli Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCPU(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint32
+		err  bool
+	}{
+		{in: "2", want: 2000},
+		{in: "0.5", want: 500},
+		{in: "500m", want: 500},
+		{in: ".25", want: 250},
+		{in: "0", err: true},
+		{in: "-1", err: true},
+		{in: "0.0001", err: true},
+		{in: "5000000", err: true},
+		{in: "2Gi", err: true},
+		{in: "two", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCPU(tt.in)
+		if tt.err {
+			assert.Error(t, err, tt.in)
+			continue
+		}
+		if assert.NoError(t, err, tt.in) {
+			assert.Equal(t, tt.want, got, tt.in)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint32
+		err  bool
+	}{
+		{in: "2", want: 2048},
+		{in: "1.5Gi", want: 1536},
+		{in: "512Mi", want: 512},
+		{in: "20G", want: 19073},
+		{in: "1Ti", want: 1 << 20},
+		{in: "0Mi", err: true},
+		{in: "-2Gi", err: true},
+		{in: "10Ki", err: true},
+		{in: "5000Ti", err: true},
+		{in: "2GB", err: true},
+		{in: "", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSize(tt.in)
+		if tt.err {
+			assert.Error(t, err, tt.in)
+			continue
+		}
+		if assert.NoError(t, err, tt.in) {
+			assert.Equal(t, tt.want, got, tt.in)
+		}
+	}
+}